@@ -0,0 +1,483 @@
+package chafa
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"time"
+)
+
+// AnimationFrame is one frame of an [Animation]. Like in the file it was
+// decoded from, a frame only holds the area it draws on, which is composited
+// with the previous frames when the animation is played.
+type AnimationFrame struct {
+	// The area of the animation drawn by the frame.
+	Bounds image.Rectangle
+
+	// How long the frame is shown.
+	Delay time.Duration
+
+	dispose byte    // One of the dispose* constants, applied after the frame is shown
+	op      draw.Op // How the frame is drawn over the previous one
+
+	// The frame's image, or a function decoding it when needed.
+	image  image.Image
+	decode func() (image.Image, error)
+}
+
+// Animation is a decoded animated image. Frames are composited one after the
+// other while playing, so only a single full frame is kept in memory.
+type Animation struct {
+	Frames []AnimationFrame
+
+	// Size of the animation in pixels.
+	Width, Height int
+
+	// How many times the animation is played. Zero means forever.
+	Loops int
+}
+
+// Frame disposal, applied to the area of a frame after it was shown.
+const (
+	disposeNone       = 0 // Leave the frame in place
+	disposeBackground = 1 // Clear the area to transparent
+	disposePrevious   = 2 // Restore the area to what it was before the frame
+)
+
+// Delay used for frames that specify none or an unreasonably short one,
+// matching what browsers and the chafa CLI do.
+const defaultFrameDelay = 100 * time.Millisecond
+
+// maxAnimationPixels limits the size of animations, so that a corrupt or
+// malicious file can't make [DecodeAnimation] allocate gigabytes.
+const maxAnimationPixels = 1 << 26
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// DecodeAnimation decodes an animated GIF or APNG from r. Still PNG and GIF
+// images, as well as any other format understood by [Decode], are returned as
+// a single frame.
+func DecodeAnimation(r io.Reader) (*Animation, error) {
+	br := bufio.NewReader(r)
+
+	magic, _ := br.Peek(8)
+	switch {
+	case bytes.HasPrefix(magic, []byte("GIF8")):
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		return decodeGIFAnimation(data)
+
+	case bytes.Equal(magic, pngSignature):
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		return decodePNGAnimation(data)
+	}
+
+	img, err := Decode(br)
+	if err != nil {
+		return nil, err
+	}
+
+	return stillAnimation(img), nil
+}
+
+// LoadAnimation decodes the animation in the file at path. See [DecodeAnimation].
+func LoadAnimation(path string) (*Animation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return DecodeAnimation(file)
+}
+
+// checkAnimationSize returns an error if an animation of width x height
+// pixels is empty or too large.
+func checkAnimationSize(width, height uint64) error {
+	if width == 0 || height == 0 || width*height > maxAnimationPixels {
+		return fmt.Errorf("chafa: unsupported animation size %dx%d", width, height)
+	}
+	return nil
+}
+
+// stillAnimation wraps img in a single frame animation.
+func stillAnimation(img image.Image) *Animation {
+	bounds := img.Bounds()
+
+	return &Animation{
+		Frames: []AnimationFrame{{
+			Bounds: image.Rect(0, 0, bounds.Dx(), bounds.Dy()),
+			op:     draw.Src,
+			image:  img,
+		}},
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		Loops:  1,
+	}
+}
+
+// frameDelay converts a frame delay, substituting the default for ones of 10ms or less.
+func frameDelay(delay time.Duration) time.Duration {
+	if delay <= 10*time.Millisecond {
+		return defaultFrameDelay
+	}
+	return delay
+}
+
+// decodeGIFAnimation decodes the frames of a GIF.
+func decodeGIFAnimation(data []byte) (*Animation, error) {
+	config, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAnimationSize(uint64(config.Width), uint64(config.Height)); err != nil {
+		return nil, err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	anim := &Animation{Width: config.Width, Height: config.Height}
+
+	// GIF counts repeats after the first play, with -1 meaning none at all
+	switch {
+	case g.LoopCount < 0:
+		anim.Loops = 1
+	case g.LoopCount > 0:
+		anim.Loops = g.LoopCount + 1
+	}
+
+	screen := image.Rect(0, 0, anim.Width, anim.Height)
+	for i, frame := range g.Image {
+		dispose := byte(disposeNone)
+		if i < len(g.Disposal) {
+			switch g.Disposal[i] {
+			case gif.DisposalBackground:
+				// The background is treated as transparent, as browsers do
+				dispose = disposeBackground
+			case gif.DisposalPrevious:
+				dispose = disposePrevious
+			}
+		}
+
+		var delay time.Duration
+		if i < len(g.Delay) {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+
+		anim.Frames = append(anim.Frames, AnimationFrame{
+			Bounds:  frame.Rect.Intersect(screen),
+			Delay:   frameDelay(delay),
+			dispose: dispose,
+			op:      draw.Over,
+			image:   frame,
+		})
+	}
+
+	if len(anim.Frames) == 0 {
+		return nil, errors.New("chafa: GIF has no frames")
+	}
+
+	return anim, nil
+}
+
+// APNG blend operations. Its dispose operations match the dispose* constants.
+const (
+	apngBlendSource = 0
+	apngBlendOver   = 1
+)
+
+// apngFrameControl is the contents of an fcTL chunk.
+type apngFrameControl struct {
+	width, height    uint32
+	xOffset, yOffset uint32
+	delayNum         uint16
+	delayDen         uint16
+	disposeOp        byte
+	blendOp          byte
+}
+
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// decodePNGAnimation decodes a PNG, which is an animation if it has an acTL
+// chunk. APNG frames are only decoded when they are drawn, each by
+// reassembling it into a standalone PNG, with the IHDR adjusted to the
+// frame's size and its fdAT chunks turned into IDAT.
+func decodePNGAnimation(data []byte) (*Animation, error) {
+	chunks, err := readPNGChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ihdr     []byte
+		header   []pngChunk // Chunks shared by all frames, e.g. PLTE and tRNS
+		animated bool
+		plays    uint32
+		frames   []apngFrameControl
+		frameDat [][]pngChunk
+		idatSeen bool
+		defaultF = -1 // Index of the frame using the IDAT data, if any
+	)
+
+	for _, c := range chunks {
+		switch c.typ {
+		case "IHDR":
+			ihdr = c.data
+		case "acTL":
+			if len(c.data) < 8 {
+				return nil, errors.New("chafa: bad APNG acTL chunk")
+			}
+			animated = true
+			plays = binary.BigEndian.Uint32(c.data[4:8])
+		case "fcTL":
+			if len(c.data) < 26 {
+				return nil, errors.New("chafa: bad APNG fcTL chunk")
+			}
+			frames = append(frames, apngFrameControl{
+				width:     binary.BigEndian.Uint32(c.data[4:]),
+				height:    binary.BigEndian.Uint32(c.data[8:]),
+				xOffset:   binary.BigEndian.Uint32(c.data[12:]),
+				yOffset:   binary.BigEndian.Uint32(c.data[16:]),
+				delayNum:  binary.BigEndian.Uint16(c.data[20:]),
+				delayDen:  binary.BigEndian.Uint16(c.data[22:]),
+				disposeOp: c.data[24],
+				blendOp:   c.data[25],
+			})
+			frameDat = append(frameDat, nil)
+		case "IDAT":
+			if !idatSeen && len(frames) > 0 {
+				defaultF = len(frames) - 1
+			}
+			idatSeen = true
+			if defaultF >= 0 {
+				frameDat[defaultF] = append(frameDat[defaultF], c)
+			}
+		case "fdAT":
+			if len(frames) == 0 || len(c.data) < 4 {
+				return nil, errors.New("chafa: bad APNG fdAT chunk")
+			}
+			i := len(frames) - 1
+			frameDat[i] = append(frameDat[i], pngChunk{"IDAT", c.data[4:]})
+		case "IEND":
+		default:
+			if !idatSeen {
+				header = append(header, c)
+			}
+		}
+	}
+
+	if len(ihdr) < 13 {
+		return nil, errors.New("chafa: bad PNG IHDR chunk")
+	}
+	width := binary.BigEndian.Uint32(ihdr[0:])
+	height := binary.BigEndian.Uint32(ihdr[4:])
+	if err := checkAnimationSize(uint64(width), uint64(height)); err != nil {
+		return nil, err
+	}
+
+	if !animated || len(frames) == 0 {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return stillAnimation(img), nil
+	}
+
+	anim := &Animation{Width: int(width), Height: int(height), Loops: int(plays)}
+
+	for i, fc := range frames {
+		if len(frameDat[i]) == 0 {
+			continue
+		}
+
+		// Frames must lie within the image, as the APNG specification requires
+		if fc.width == 0 || fc.height == 0 ||
+			uint64(fc.xOffset)+uint64(fc.width) > uint64(width) ||
+			uint64(fc.yOffset)+uint64(fc.height) > uint64(height) {
+			return nil, fmt.Errorf("chafa: APNG frame %d lies outside the image", i)
+		}
+		if fc.disposeOp > disposePrevious || fc.blendOp > apngBlendOver {
+			return nil, fmt.Errorf("chafa: APNG frame %d has an unknown dispose or blend operation", i)
+		}
+
+		dispose := fc.disposeOp
+		if dispose == disposePrevious && len(anim.Frames) == 0 {
+			dispose = disposeBackground
+		}
+
+		op := draw.Over
+		if fc.blendOp == apngBlendSource {
+			op = draw.Src
+		}
+
+		den := time.Duration(fc.delayDen)
+		if den == 0 {
+			den = 100
+		}
+		delay := time.Duration(fc.delayNum) * time.Second / den
+
+		index, fc, idat := i, fc, frameDat[i]
+		anim.Frames = append(anim.Frames, AnimationFrame{
+			Bounds:  image.Rect(0, 0, int(fc.width), int(fc.height)).Add(image.Pt(int(fc.xOffset), int(fc.yOffset))),
+			Delay:   frameDelay(delay),
+			dispose: dispose,
+			op:      op,
+			decode: func() (image.Image, error) {
+				img, err := decodeAPNGFrame(ihdr, header, fc, idat)
+				if err != nil {
+					return nil, fmt.Errorf("chafa: APNG frame %d: %w", index, err)
+				}
+				return img, nil
+			},
+		})
+	}
+
+	if len(anim.Frames) == 0 {
+		return nil, errors.New("chafa: APNG has no frames")
+	}
+
+	return anim, nil
+}
+
+// decodeAPNGFrame decodes one APNG frame as a standalone PNG.
+func decodeAPNGFrame(ihdr []byte, header []pngChunk, fc apngFrameControl, idat []pngChunk) (image.Image, error) {
+	frameHeader := bytes.Clone(ihdr)
+	binary.BigEndian.PutUint32(frameHeader[0:], fc.width)
+	binary.BigEndian.PutUint32(frameHeader[4:], fc.height)
+
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+	writePNGChunk(&buf, "IHDR", frameHeader)
+	for _, c := range header {
+		writePNGChunk(&buf, c.typ, c.data)
+	}
+	for _, c := range idat {
+		writePNGChunk(&buf, c.typ, c.data)
+	}
+	writePNGChunk(&buf, "IEND", nil)
+
+	return png.Decode(&buf)
+}
+
+// readPNGChunks splits a PNG file into its chunks, without checking CRCs.
+func readPNGChunks(data []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("chafa: not a PNG file")
+	}
+	data = data[len(pngSignature):]
+
+	var chunks []pngChunk
+	for len(data) >= 12 {
+		length := binary.BigEndian.Uint32(data)
+		if uint64(length)+12 > uint64(len(data)) {
+			return nil, errors.New("chafa: truncated PNG chunk")
+		}
+
+		chunks = append(chunks, pngChunk{
+			typ:  string(data[4:8]),
+			data: data[8 : 8+length],
+		})
+		data = data[12+length:]
+	}
+
+	return chunks, nil
+}
+
+func writePNGChunk(w *bytes.Buffer, typ string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], typ)
+	w.Write(header[:])
+	w.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
+
+// compositor draws the frames of an animation, one after the other, on a
+// single image.
+type compositor struct {
+	anim   *Animation
+	screen *image.NRGBA
+	next   int // Index of the next frame to draw
+
+	// Area of the last frame drawn, and for disposePrevious what was under it
+	last  *AnimationFrame
+	saved *image.NRGBA
+}
+
+func newCompositor(anim *Animation) *compositor {
+	return &compositor{anim: anim}
+}
+
+// frame returns the animation as it looks on frame index. Going forward only
+// draws the frames in between; going back starts over from the first frame.
+// The image is reused by later calls.
+func (c *compositor) frame(index int) (*image.NRGBA, error) {
+	if c.screen == nil || index < c.next-1 {
+		c.screen = image.NewNRGBA(image.Rect(0, 0, c.anim.Width, c.anim.Height))
+		c.next, c.last, c.saved = 0, nil, nil
+	}
+
+	for c.next <= index {
+		if err := c.step(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.screen, nil
+}
+
+// step disposes of the last frame and draws the next one.
+func (c *compositor) step() error {
+	if last := c.last; last != nil {
+		switch last.dispose {
+		case disposeBackground:
+			draw.Draw(c.screen, last.Bounds, image.Transparent, image.Point{}, draw.Src)
+		case disposePrevious:
+			draw.Draw(c.screen, last.Bounds, c.saved, last.Bounds.Min, draw.Src)
+		}
+	}
+
+	frame := &c.anim.Frames[c.next]
+	img := frame.image
+	if img == nil {
+		var err error
+		if img, err = frame.decode(); err != nil {
+			return err
+		}
+	}
+
+	if frame.dispose == disposePrevious {
+		c.saved = image.NewNRGBA(frame.Bounds)
+		draw.Draw(c.saved, frame.Bounds, c.screen, frame.Bounds.Min, draw.Src)
+	}
+
+	draw.Draw(c.screen, frame.Bounds, img, img.Bounds().Min, frame.op)
+	c.last = frame
+	c.next++
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package chafa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	red         = color.NRGBA{0xff, 0, 0, 0xff}
+	green       = color.NRGBA{0, 0xff, 0, 0xff}
+	blue        = color.NRGBA{0, 0, 0xff, 0xff}
+	transparent = color.NRGBA{}
+)
+
+func solidNRGBA(width, height int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+// apngFrame describes a frame for buildAPNG.
+type apngFrame struct {
+	img              *image.NRGBA
+	xOffset, yOffset uint32
+	disposeOp        byte
+	blendOp          byte
+}
+
+// buildAPNG assembles an APNG of width x height pixels from frames, the first
+// of which doubles as the default image.
+func buildAPNG(t *testing.T, width, height uint32, frames []apngFrame) []byte {
+	t.Helper()
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+
+	seq := uint32(0)
+	for i, f := range frames {
+		var encoded bytes.Buffer
+		if err := png.Encode(&encoded, f.img); err != nil {
+			t.Fatal(err)
+		}
+		chunks, err := readPNGChunks(encoded.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 0 {
+			ihdr := bytes.Clone(chunks[0].data)
+			binary.BigEndian.PutUint32(ihdr[0:], width)
+			binary.BigEndian.PutUint32(ihdr[4:], height)
+			writePNGChunk(&out, "IHDR", ihdr)
+
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+			writePNGChunk(&out, "acTL", actl)
+		}
+
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(f.img.Rect.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(f.img.Rect.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:], f.xOffset)
+		binary.BigEndian.PutUint32(fctl[16:], f.yOffset)
+		binary.BigEndian.PutUint16(fctl[20:], 5)
+		binary.BigEndian.PutUint16(fctl[22:], 100)
+		fctl[24], fctl[25] = f.disposeOp, f.blendOp
+		writePNGChunk(&out, "fcTL", fctl)
+		seq++
+
+		for _, c := range chunks {
+			if c.typ != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				writePNGChunk(&out, "IDAT", c.data)
+			} else {
+				writePNGChunk(&out, "fdAT", append(binary.BigEndian.AppendUint32(nil, seq), c.data...))
+				seq++
+			}
+		}
+	}
+	writePNGChunk(&out, "IEND", nil)
+
+	return out.Bytes()
+}
+
+// checkFrame checks the colors of some pixels of frame index, composited by c.
+func checkFrame(t *testing.T, c *compositor, index int, want map[image.Point]color.NRGBA) {
+	t.Helper()
+
+	img, err := c.frame(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pt, wantColor := range want {
+		if got := img.NRGBAAt(pt.X, pt.Y); got != wantColor {
+			t.Errorf("frame %d at %v: got %v, want %v", index, pt, got, wantColor)
+		}
+	}
+}
+
+func TestDecodeAPNG(t *testing.T) {
+	data := buildAPNG(t, 4, 4, []apngFrame{
+		{img: solidNRGBA(4, 4, red), blendOp: apngBlendSource},
+		{img: solidNRGBA(2, 2, green), xOffset: 2, yOffset: 2, disposeOp: disposePrevious, blendOp: apngBlendOver},
+		{img: solidNRGBA(1, 1, blue), disposeOp: disposeBackground, blendOp: apngBlendOver},
+	})
+
+	anim, err := DecodeAnimation(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Frames) != 3 || anim.Width != 4 || anim.Height != 4 || anim.Loops != 0 {
+		t.Fatalf("got %d frames of %dx%d looping %d times, want 3 of 4x4 looping forever",
+			len(anim.Frames), anim.Width, anim.Height, anim.Loops)
+	}
+	if d := anim.Frames[0].Delay; d != 50*time.Millisecond {
+		t.Errorf("frame delay %v, want 50ms", d)
+	}
+
+	c := newCompositor(anim)
+	checkFrame(t, c, 0, map[image.Point]color.NRGBA{{0, 0}: red, {3, 3}: red})
+	checkFrame(t, c, 1, map[image.Point]color.NRGBA{{0, 0}: red, {3, 3}: green})
+	// Frame 1 is disposed of by restoring what was under it
+	checkFrame(t, c, 2, map[image.Point]color.NRGBA{{0, 0}: blue, {3, 3}: red, {1, 1}: red})
+	// Seeking back starts over
+	checkFrame(t, c, 1, map[image.Point]color.NRGBA{{0, 0}: red, {3, 3}: green})
+}
+
+func TestDecodeGIFAnimation(t *testing.T) {
+	palette := color.Palette{transparent, red, green, blue}
+	frame := func(rect image.Rectangle, index uint8) *image.Paletted {
+		img := image.NewPaletted(rect, palette)
+		for i := range img.Pix {
+			img.Pix[i] = index
+		}
+		return img
+	}
+
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image: []*image.Paletted{
+			frame(image.Rect(0, 0, 4, 4), 1),
+			frame(image.Rect(0, 0, 2, 2), 2),
+			frame(image.Rect(2, 2, 4, 4), 3),
+		},
+		Delay:     []int{5, 0, 20},
+		Disposal:  []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone},
+		LoopCount: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anim, err := DecodeAnimation(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Frames) != 3 || anim.Loops != 3 {
+		t.Fatalf("got %d frames looping %d times, want 3 looping 3 times", len(anim.Frames), anim.Loops)
+	}
+	if d := anim.Frames[1].Delay; d != defaultFrameDelay {
+		t.Errorf("frame delay %v, want the default %v", d, defaultFrameDelay)
+	}
+
+	c := newCompositor(anim)
+	checkFrame(t, c, 0, map[image.Point]color.NRGBA{{0, 0}: red, {3, 3}: red})
+	checkFrame(t, c, 1, map[image.Point]color.NRGBA{{0, 0}: green, {3, 3}: red})
+	// Frame 1 is cleared to transparent
+	checkFrame(t, c, 2, map[image.Point]color.NRGBA{{0, 0}: transparent, {2, 0}: red, {3, 3}: blue})
+}
+
+func TestDecodeAPNGRejectsBadSizes(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height uint32
+		frames        []apngFrame
+		want          string
+	}{
+		{
+			name:  "huge image",
+			width: 1 << 30, height: 1 << 30,
+			frames: []apngFrame{{img: solidNRGBA(1, 1, red)}},
+			want:   "unsupported animation size",
+		},
+		{
+			name:  "frame offset out of range",
+			width: 4, height: 4,
+			frames: []apngFrame{
+				{img: solidNRGBA(4, 4, red)},
+				{img: solidNRGBA(2, 2, green), xOffset: 1 << 31},
+			},
+			want: "outside the image",
+		},
+		{
+			name:  "frame larger than the image",
+			width: 4, height: 4,
+			frames: []apngFrame{{img: solidNRGBA(8, 8, red)}},
+			want:   "outside the image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeAnimation(bytes.NewReader(buildAPNG(t, tt.width, tt.height, tt.frames)))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package chafa
 
-import "unsafe"
+import (
+	"image"
+	"io"
+	"sync"
+	"unsafe"
+)
 
 var (
 	// Creates a new canvas with the specified configuration. The canvas makes
@@ -42,9 +47,12 @@ var (
 	// is determined by the configuration assigned to canvas on its creation.
 	//
 	// All output lines except for the last one will end in a newline.
+	//
+	// The returned GString is owned by the caller, and its contents are only valid
+	// until it is freed. [CanvasPrintString] copies the result and frees it.
 	CanvasPrint func(canvas *Canvas, termInfo *TermInfo) *GString
 
-	//Builds an array of UTF-8 strings made up of terminal control sequences and symbols
+	// Builds an array of UTF-8 strings made up of terminal control sequences and symbols
 	// representing the canvas' current contents. These can be printed to a terminal.
 	// The exact choice of escape sequences and symbols, dimensions, etc. is determined
 	// by the configuration assigned to canvas on its creation.
@@ -58,6 +66,8 @@ var (
 	//
 	// In other pixel modes, there may be one or more strings, but the splitting
 	// criteria should not be relied on. They must be printed in sequence, exactly as they appear.
+	//
+	// The array must be freed with [FreeGStringArray]. [CanvasPrintTo] does this for you.
 	CanvasPrintRows func(canvas *Canvas, termInfo *TermInfo, arrayOut *unsafe.Pointer, lenOut *int32)
 
 	// Builds an array of UTF-8 strings made up of terminal control sequences and symbols
@@ -73,6 +83,8 @@ var (
 	// In other pixel modes, there may be one or more strings,
 	// but the splitting criteria should not be relied on.
 	// They must be printed in sequence, exactly as they appear.
+	//
+	// The array must be freed with g_strfreev. [CanvasPrintRowsGo] does this for you.
 	CanvasPrintRowsStrv func(canvas *Canvas, termInfo *TermInfo) unsafe.Pointer
 
 	// Returns the character at cell (x, y). The coordinates are zero-indexed.
@@ -124,6 +136,165 @@ var (
 	CanvasSetRawColorsAt func(canvas *Canvas, x, y, fg, bg int32)
 )
 
+// Replaces pixel data of canvas with that of img.
+//
+// *image.RGBA and *image.NRGBA are handed to libchafa without conversion, as
+// [CHAFA_PIXEL_RGBA8_PREMULTIPLIED] and [CHAFA_PIXEL_RGBA8_UNASSOCIATED]
+// respectively. *image.Gray, *image.YCbCr and *image.Paletted are converted
+// directly to a matching [PixelType]; other images are drawn into an *image.RGBA first.
+func CanvasDrawImage(canvas *Canvas, img image.Image) {
+	pixels, pixelType, width, height, rowstride := imagePixels(img)
+	CanvasDrawAllPixels(canvas, pixelType, pixels, width, height, rowstride)
+}
+
+// Builds a string of terminal control sequences and symbols representing the canvas'
+// current contents, like [CanvasPrint]. The result is copied to Go memory and the
+// C buffer is freed, so unlike CanvasPrint this does not leak.
+func CanvasPrintString(canvas *Canvas, termInfo *TermInfo) string {
+	gstr := CanvasPrint(canvas, termInfo)
+	return gStringFreeToGo(gstr)
+}
+
+// Builds the canvas' current contents as a slice of strings of terminal control
+// sequences and symbols, copied to Go memory. The C array is freed.
+//
+// When the canvas' pixel mode is [CHAFA_PIXEL_MODE_SYMBOLS], each element holds
+// exactly one symbol row, in order from the top, so there are as many elements as
+// the canvas is tall. Rows carry no separators, newlines or cursor movement, which
+// lets callers position each row themselves, e.g. inside a TUI layout.
+//
+// In other pixel modes, there may be one or more strings, but the splitting
+// criteria should not be relied on. They must be printed in sequence, exactly as they appear.
+//
+// This needs libchafa 1.14 or newer; with older versions the error matches errors.ErrUnsupported.
+func CanvasPrintRowsGo(canvas *Canvas, termInfo *TermInfo) ([]string, error) {
+	if err := checkFunction("chafa_canvas_print_rows_strv"); err != nil {
+		return nil, err
+	}
+
+	strv := CanvasPrintRowsStrv(canvas, termInfo)
+	return gStrvFreeToGo(strv), nil
+}
+
+// Places placement on canvas like [CanvasSetPlacement], but returns an error
+// if the loaded libchafa does not support placements (it needs 1.14 or newer).
+func CanvasSetPlacementGo(canvas *Canvas, placement *Placement) error {
+	if err := checkFunction("chafa_canvas_set_placement"); err != nil {
+		return err
+	}
+
+	CanvasSetPlacement(canvas, placement)
+	return nil
+}
+
+// Returns the character at cell (x, y) like [CanvasGetCharAt], but returns an
+// error if the loaded libchafa lacks chafa_canvas_get_char_at (it needs 1.8 or newer).
+func CanvasGetCharAtGo(canvas *Canvas, x, y int32) (rune, error) {
+	if err := checkFunction("chafa_canvas_get_char_at"); err != nil {
+		return 0, err
+	}
+
+	return CanvasGetCharAt(canvas, x, y), nil
+}
+
+// Returns the raw colors at cell (x, y) like [CanvasGetRawColorsAt], but returns
+// an error if the loaded libchafa lacks chafa_canvas_get_raw_colors_at (it needs 1.8 or newer).
+func CanvasGetRawColorsAtGo(canvas *Canvas, x, y int32) (fg, bg int32, err error) {
+	if err := checkFunction("chafa_canvas_get_raw_colors_at"); err != nil {
+		return 0, 0, err
+	}
+
+	CanvasGetRawColorsAt(canvas, x, y, &fg, &bg)
+	return fg, bg, nil
+}
+
+// printBufferSize is the size of the buffers [CanvasPrintTo] collects short rows
+// in before writing them out. Longer rows are written directly.
+const printBufferSize = 32 << 10
+
+var printBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, printBufferSize)
+		return &buf
+	},
+}
+
+// Writes the canvas' current contents to w as terminal control sequences and
+// symbols, producing the same output as [CanvasPrint]. It returns the number of
+// bytes written and the first error encountered.
+//
+// The output is produced row by row and streamed to w without building it up as
+// one string, so large pixel mode images are not held in memory twice, and a
+// slow writer applies backpressure. Short rows are batched in a reused buffer.
+//
+// With libchafa older than 1.14, which lacks chafa_canvas_print_rows, the output
+// is built with CanvasPrint and written in one go.
+func CanvasPrintTo(w io.Writer, canvas *Canvas, termInfo *TermInfo) (int64, error) {
+	if !HasFunction("chafa_canvas_print_rows") || !HasFunction("chafa_free_gstring_array") {
+		n, err := io.WriteString(w, CanvasPrintString(canvas, termInfo))
+		return int64(n), err
+	}
+
+	var array unsafe.Pointer
+	var length int32
+	CanvasPrintRows(canvas, termInfo, &array, &length)
+	defer FreeGStringArray(array)
+
+	// Symbol rows carry no newlines of their own
+	separate := CanvasConfigGetPixelMode(CanvasPeekConfig(canvas)) == CHAFA_PIXEL_MODE_SYMBOLS
+
+	bufp := printBufferPool.Get().(*[]byte)
+	defer printBufferPool.Put(bufp)
+	buf := (*bufp)[:0]
+
+	var written int64
+	write := func(p []byte) error {
+		n, err := w.Write(p)
+		written += int64(n)
+		return err
+	}
+
+	for i, row := range unsafe.Slice((**GString)(array), length) {
+		// The row is written straight from C memory; writers may not retain it
+		data := unsafe.Slice(unsafe.StringData(row.str), len(row.str))
+
+		if separate && i > 0 {
+			buf = append(buf, '\n')
+		}
+
+		if len(buf)+len(data) > cap(buf) {
+			if err := write(buf); err != nil {
+				return written, err
+			}
+			buf = buf[:0]
+		}
+
+		if len(data) >= cap(buf) {
+			if err := write(data); err != nil {
+				return written, err
+			}
+			continue
+		}
+
+		buf = append(buf, data...)
+	}
+
+	if len(buf) > 0 {
+		if err := write(buf); err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// WriteTo writes the canvas' current contents to w, using fallback control
+// sequences. It implements io.WriterTo; use [CanvasPrintTo] to write output
+// for a specific [TermInfo].
+func (canvas *Canvas) WriteTo(w io.Writer) (int64, error) {
+	return CanvasPrintTo(w, canvas, nil)
+}
+
 type Canvas struct {
 	Refs int32
 
@@ -0,0 +1,224 @@
+package chafa
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// residentBytes returns the resident set size of the process.
+func residentBytes(t *testing.T) int64 {
+	t.Helper()
+
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		t.Skipf("cannot measure memory use: %v", err)
+	}
+	fields := strings.Fields(string(data))
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pages * int64(os.Getpagesize())
+}
+
+// TestCanvasPrintStringDoesNotLeak renders thousands of frames and checks
+// that the GStrings returned by libchafa are freed.
+func TestCanvasPrintStringDoesNotLeak(t *testing.T) {
+	requireLibrary(t)
+	if runtime.GOOS != "linux" {
+		t.Skip("memory use is measured through /proc")
+	}
+
+	const (
+		frames  = 2000
+		maxGrow = 8 << 20
+	)
+
+	config := CanvasConfigNew()
+	defer CanvasConfigUnref(config)
+	CanvasConfigSetGeometry(config, 40, 12)
+	CanvasConfigSetCanvasMode(config, CHAFA_CANVAS_MODE_TRUECOLOR)
+
+	canvas := CanvasNew(config)
+	defer CanvasUnRef(canvas)
+
+	termInfo := TermDbGetFallbackInfo(TermDbGetDefault())
+	defer TermInfoUnref(termInfo)
+
+	images := []*image.NRGBA{testImage(80, 48, 0), testImage(80, 48, 64)}
+
+	print := func(i int) int {
+		CanvasDrawImage(canvas, images[i%len(images)])
+		return len(CanvasPrintString(canvas, termInfo))
+	}
+
+	// Warm up, so that caches and arenas are in place before measuring
+	for i := 0; i < 50; i++ {
+		print(i)
+	}
+	runtime.GC()
+	debug.FreeOSMemory()
+	before := residentBytes(t)
+
+	var total int
+	for i := 0; i < frames; i++ {
+		total += print(i)
+	}
+	runtime.GC()
+	debug.FreeOSMemory()
+	grown := residentBytes(t) - before
+
+	t.Logf("printed %d bytes in %d frames, resident set grew by %d bytes", total, frames, grown)
+	if grown > maxGrow {
+		t.Errorf("resident set grew by %d bytes after %d frames, more than %d", grown, frames, maxGrow)
+	}
+}
+
+// newTestCanvas returns a width x height canvas in mode, with testImage drawn
+// on it.
+func newTestCanvas(t *testing.T, width, height int32, mode PixelMode) *Canvas {
+	t.Helper()
+
+	config := CanvasConfigNew()
+	defer CanvasConfigUnref(config)
+	CanvasConfigSetGeometry(config, width, height)
+	CanvasConfigSetCanvasMode(config, CHAFA_CANVAS_MODE_TRUECOLOR)
+	CanvasConfigSetPixelMode(config, mode)
+
+	canvas := CanvasNew(config)
+	t.Cleanup(func() { CanvasUnRef(canvas) })
+	CanvasDrawImage(canvas, testImage(int(width)*2, int(height)*4, 32))
+
+	return canvas
+}
+
+func TestCanvasPrintRowsGo(t *testing.T) {
+	requireLibrary(t)
+
+	canvas := newTestCanvas(t, 12, 5, CHAFA_PIXEL_MODE_SYMBOLS)
+	termInfo := TermDbGetFallbackInfo(TermDbGetDefault())
+	defer TermInfoUnref(termInfo)
+
+	rows, err := CanvasPrintRowsGo(canvas, termInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+	if got, want := strings.Join(rows, "\n"), CanvasPrintString(canvas, termInfo); got != want {
+		t.Errorf("joined rows differ from CanvasPrintString\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestCanvasPrintRowsGoUnsupported(t *testing.T) {
+	requireLibrary(t)
+
+	lookup := findFunction
+	findFunction = func(lib uintptr, name string) (uintptr, error) {
+		if name == "chafa_canvas_print_rows_strv" {
+			return 0, nil
+		}
+		return lookup(lib, name)
+	}
+	defer func() { findFunction = lookup }()
+
+	rows, err := CanvasPrintRowsGo(nil, nil)
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Func != "chafa_canvas_print_rows_strv" || !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("CanvasPrintRowsGo = %v, want an *UnsupportedError for chafa_canvas_print_rows_strv", err)
+	}
+	if rows != nil {
+		t.Errorf("CanvasPrintRowsGo returned %d rows with an error", len(rows))
+	}
+}
+
+// limitedWriter accepts up to limit bytes in total, then fails.
+type limitedWriter struct {
+	bytes.Buffer
+	limit int
+}
+
+var errWriterFull = errors.New("writer full")
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if room := w.limit - w.Len(); len(p) > room {
+		w.Buffer.Write(p[:room])
+		return room, errWriterFull
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestCanvasPrintTo(t *testing.T) {
+	requireLibrary(t)
+
+	tests := []struct {
+		name string
+		mode PixelMode
+		env  []string
+	}{
+		{"symbols", CHAFA_PIXEL_MODE_SYMBOLS, []string{"TERM=xterm-256color", "COLORTERM=truecolor"}},
+		{"kitty", CHAFA_PIXEL_MODE_KITTY, []string{"TERM=xterm-kitty", "KITTY_WINDOW_ID=1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := newTestCanvas(t, 40, 12, tt.mode)
+			termInfo := TermDbDetect(TermDbGetDefault(), tt.env)
+			defer TermInfoUnref(termInfo)
+
+			want := CanvasPrintString(canvas, termInfo)
+
+			var out bytes.Buffer
+			n, err := CanvasPrintTo(&out, canvas, termInfo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != want {
+				t.Errorf("CanvasPrintTo wrote %d bytes that differ from CanvasPrintString's %d", out.Len(), len(want))
+			}
+			if n != int64(out.Len()) {
+				t.Errorf("CanvasPrintTo returned %d, wrote %d bytes", n, out.Len())
+			}
+
+			out.Reset()
+			n, err = canvas.WriteTo(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := CanvasPrintString(canvas, nil); out.String() != want {
+				t.Errorf("WriteTo wrote %d bytes that differ from CanvasPrintString's %d", out.Len(), len(want))
+			}
+			if n != int64(out.Len()) {
+				t.Errorf("WriteTo returned %d, wrote %d bytes", n, out.Len())
+			}
+		})
+	}
+}
+
+func TestCanvasPrintToWriterError(t *testing.T) {
+	requireLibrary(t)
+
+	canvas := newTestCanvas(t, 40, 12, CHAFA_PIXEL_MODE_SYMBOLS)
+	termInfo := TermDbGetFallbackInfo(TermDbGetDefault())
+	defer TermInfoUnref(termInfo)
+
+	full := len(CanvasPrintString(canvas, termInfo))
+	for _, limit := range []int{0, 100, full / 2, full - 1} {
+		w := &limitedWriter{limit: limit}
+		n, err := CanvasPrintTo(w, canvas, termInfo)
+		if !errors.Is(err, errWriterFull) {
+			t.Errorf("limit %d: got error %v, want %v", limit, err, errWriterFull)
+		}
+		if n != int64(w.Len()) || w.Len() != limit {
+			t.Errorf("limit %d: returned %d, wrote %d bytes", limit, n, w.Len())
+		}
+	}
+}
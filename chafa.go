@@ -1,1109 +1,584 @@
 package chafa
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/draw"
-	"image/gif"
-	"image/jpeg"
-	"image/png"
-	"os"
-	"path/filepath"
-	"runtime"
+	"reflect"
 	"strings"
 	"sync"
 
 	"github.com/ebitengine/purego"
 )
 
-const libName = "libchafa"
+// ErrLibraryNotLoaded is reported when libchafa could not be loaded. Errors
+// returned by [Init] match it with errors.Is.
+var ErrLibraryNotLoaded = errors.New("chafa: library not loaded")
 
-func loadLibrary() (uintptr, error) {
-	// Try to extract embedded library first
-	libPath, err := extractEmbeddedLibrary()
-	if err == nil {
-		// Successfully extracted embedded library, try to load it
-		slib, err := purego.Dlopen(libPath, purego.RTLD_NOW|purego.RTLD_GLOBAL)
-		if err == nil {
-			return slib, nil
-		}
-		// If loading failed, log the error and fall back to system paths
-		fmt.Printf("Warning: Failed to load embedded library: %v\n", err)
-	} else {
-		fmt.Printf("Warning: Failed to extract embedded library: %v\n", err)
-	}
+// LoadError describes why libchafa could not be loaded.
+type LoadError struct {
+	Err error
+}
 
-	// Fall back to original behavior for compatibility
-	var libraryName string
-	switch runtime.GOOS {
-	case "darwin":
-		libraryName = fmt.Sprintf("%s.dylib", libName)
-	case "linux":
-		libraryName = fmt.Sprintf("%s.so", libName)
-	default:
-		return 0, fmt.Errorf("GOOS=%s is not supported", runtime.GOOS)
-	}
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrLibraryNotLoaded, e.Err)
+}
 
-	libPath = os.Getenv("LD_LIBRARY_PATH")
-	paths := strings.Split(libPath, ":")
-	cwd, err := os.Getwd()
-	if err != nil {
-		return 0, err
-	}
-	paths = append(paths, cwd)
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func (e *LoadError) Is(target error) bool {
+	return target == ErrLibraryNotLoaded
+}
 
-	for _, path := range paths {
-		libPath := filepath.Join(path, libraryName)
-		if _, err := os.Stat(libPath); err == nil {
-			slib, dlerr := purego.Dlopen(libPath, purego.RTLD_NOW|purego.RTLD_GLOBAL)
-			if dlerr != nil {
-				return 0, fmt.Errorf("failed to load library at %s: %w", libPath, dlerr)
+// UnsupportedError is returned for a function that is not exported by the
+// loaded libchafa, usually because it is older than the release the package
+// was written against. It matches errors.ErrUnsupported with errors.Is.
+//
+// The raw bindings of such functions do nothing and return zero values. Use
+// [HasFunction] to check before calling them, or the wrappers ending in Go,
+// e.g. [CanvasSetPlacementGo], which return an UnsupportedError instead.
+type UnsupportedError struct {
+	// Name of the missing C function, e.g. "chafa_canvas_print_rows".
+	Func string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("chafa: %s is not available in the loaded libchafa (version %s)", e.Func, Version())
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == errors.ErrUnsupported
+}
+
+var (
+	libOnce    sync.Once
+	libErr     error
+	libHandle  uintptr
+	libVersion string
+)
+
+// Init loads libchafa and binds all of the package's functions to it. It is
+// safe to call Init more than once; only the first call does any work, and
+// later calls return the same result, which matches [ErrLibraryNotLoaded]
+// with errors.Is if loading failed.
+//
+// Programs must call Init and check its result before using the bindings,
+// and before using them from multiple goroutines, since Init replaces the
+// exported function variables with the C functions. The Go APIs built on
+// the bindings, such as [NewRenderer], call Init themselves and return its
+// error.
+//
+// A binding called without Init loads the library on first use. If that
+// fails, the binding cannot return the error, so it panics with the
+// [*LoadError] Init would have returned.
+func Init() error {
+	libOnce.Do(func() {
+		libchafa, err := loadLibrary()
+		if err != nil {
+			libErr = &LoadError{Err: err}
+			return
+		}
+
+		syms := make([]uintptr, len(libraryFuncs))
+		var missing []string
+		for i, f := range libraryFuncs {
+			sym, err := lookupSymbol(libchafa, f.name)
+			if err == nil {
+				syms[i] = sym
+			}
+			if syms[i] == 0 && requiredFuncs[f.name] {
+				missing = append(missing, f.name)
+			}
+		}
+		if len(missing) > 0 {
+			libErr = &LoadError{Err: fmt.Errorf("incompatible libchafa, missing %s", strings.Join(missing, ", "))}
+			return
+		}
+
+		for i, f := range libraryFuncs {
+			if syms[i] == 0 {
+				bindUnsupported(f.fptr, f.name)
+				continue
 			}
-			return slib, nil
+			purego.RegisterFunc(f.fptr, syms[i])
 		}
+
+		bindGLib(libchafa)
+
+		libHandle = libchafa
+		libVersion = detectVersion(libchafa)
+
+		libraryLoaded()
+	})
+
+	return libErr
+}
+
+// MustInit is like [Init] but panics if the library cannot be loaded.
+func MustInit() {
+	if err := Init(); err != nil {
+		panic(err)
 	}
-	return 0, fmt.Errorf("%s library not found in LD_LIBRARY_PATH or CWD", libName)
 }
 
-var libOnce sync.Once
+type libraryFunc struct {
+	fptr any
+	name string
+}
 
 func init() {
-	libOnce.Do(func() {
-		libchafa, err := loadLibrary()
-		if err != nil {
+	for _, f := range libraryFuncs {
+		bindLazily(f.fptr)
+	}
+}
+
+// bindLazily points the function variable at fptr to a stub which loads the
+// library on first use and then forwards the call to the real function,
+// which Init has bound to the variable by then. If the library cannot be
+// loaded, the stub panics with the [*LoadError].
+func bindLazily(fptr any) {
+	fn := reflect.ValueOf(fptr).Elem()
+	stub := reflect.MakeFunc(fn.Type(), func(args []reflect.Value) []reflect.Value {
+		if err := Init(); err != nil {
 			panic(err)
 		}
+		if fn.Type().IsVariadic() {
+			return fn.CallSlice(args)
+		}
+		return fn.Call(args)
+	})
+	fn.Set(stub)
+}
 
-		// Canvas
-		purego.RegisterLibFunc(&CanvasNew, libchafa, "chafa_canvas_new")
-		purego.RegisterLibFunc(&CanvasNewSimilar, libchafa, "chafa_canvas_new_similar")
-		purego.RegisterLibFunc(&CanvasRef, libchafa, "chafa_canvas_ref")
-		purego.RegisterLibFunc(&CanvasUnRef, libchafa, "chafa_canvas_unref")
-		purego.RegisterLibFunc(&CanvasPeekConfig, libchafa, "chafa_canvas_peek_config")
-		purego.RegisterLibFunc(&CanvasSetPlacement, libchafa, "chafa_canvas_set_placement")
-		purego.RegisterLibFunc(&CanvasDrawAllPixels, libchafa, "chafa_canvas_draw_all_pixels")
-		purego.RegisterLibFunc(&CanvasPrint, libchafa, "chafa_canvas_print")
-		purego.RegisterLibFunc(&CanvasPrintRows, libchafa, "chafa_canvas_print_rows")
-		purego.RegisterLibFunc(&CanvasPrintRowsStrv, libchafa, "chafa_canvas_print_rows_strv")
-		purego.RegisterLibFunc(&CanvasGetCharAt, libchafa, "chafa_canvas_get_char_at")
-		purego.RegisterLibFunc(&CanvasSetCharAt, libchafa, "chafa_canvas_set_char_at")
-		purego.RegisterLibFunc(&CanvasGetColorsAt, libchafa, "chafa_canvas_get_colors_at")
-		purego.RegisterLibFunc(&CanvasSetColorsAt, libchafa, "chafa_canvas_set_colors_at")
-		purego.RegisterLibFunc(&CanvasGetRawColorsAt, libchafa, "chafa_canvas_get_raw_colors_at")
-		purego.RegisterLibFunc(&CanvasSetRawColorsAt, libchafa, "chafa_canvas_set_raw_colors_at")
+// zeroResults returns the zero values of the results of the function type typ.
+func zeroResults(typ reflect.Type) []reflect.Value {
+	results := make([]reflect.Value, typ.NumOut())
+	for i := range results {
+		results[i] = reflect.Zero(typ.Out(i))
+	}
+	return results
+}
 
-		// Config
-		purego.RegisterLibFunc(&CanvasConfigNew, libchafa, "chafa_canvas_config_new")
-		purego.RegisterLibFunc(&CanvasConfigCopy, libchafa, "chafa_canvas_config_copy")
-		purego.RegisterLibFunc(&CanvasConfigRef, libchafa, "chafa_canvas_config_ref")
-		purego.RegisterLibFunc(&CanvasConfigUnref, libchafa, "chafa_canvas_config_unref")
-		purego.RegisterLibFunc(
-			&CanvasConfigGetGeometry,
-			libchafa,
-			"chafa_canvas_config_get_geometry",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetGeometry,
-			libchafa,
-			"chafa_canvas_config_set_geometry",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetCellGeometry,
-			libchafa,
-			"chafa_canvas_config_get_cell_geometry",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetCellGeometry,
-			libchafa,
-			"chafa_canvas_config_set_cell_geometry",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetPixelMode,
-			libchafa,
-			"chafa_canvas_config_get_pixel_mode",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetPixelMode,
-			libchafa,
-			"chafa_canvas_config_set_pixel_mode",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetCanvasMode,
-			libchafa,
-			"chafa_canvas_config_get_canvas_mode",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetCanvasMode,
-			libchafa,
-			"chafa_canvas_config_set_canvas_mode",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetColorExtractor,
-			libchafa,
-			"chafa_canvas_config_get_color_extractor",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetColorExtractor,
-			libchafa,
-			"chafa_canvas_config_set_color_extractor",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetColorSpace,
-			libchafa,
-			"chafa_canvas_config_get_color_space",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetColorSpace,
-			libchafa,
-			"chafa_canvas_config_set_color_space",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetPreprocessingEnabled,
-			libchafa,
-			"chafa_canvas_config_get_preprocessing_enabled",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetPreprocessingEnabled,
-			libchafa,
-			"chafa_canvas_config_set_preprocessing_enabled",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigPeekSymbolMap,
-			libchafa,
-			"chafa_canvas_config_peek_symbol_map",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetSymbolMap,
-			libchafa,
-			"chafa_canvas_config_set_symbol_map",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigPeekFillSymbolMap,
-			libchafa,
-			"chafa_canvas_config_peek_fill_symbol_map",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetTransparencyThreshold,
-			libchafa,
-			"chafa_canvas_config_get_transparency_threshold",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetTransparencyThreshold,
-			libchafa,
-			"chafa_canvas_config_set_transparency_threshold",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetFgOnlyEnabled,
-			libchafa,
-			"chafa_canvas_config_get_fg_only_enabled",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetFgOnlyEnabled,
-			libchafa,
-			"chafa_canvas_config_set_fg_only_enabled",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetFgColor,
-			libchafa,
-			"chafa_canvas_config_get_fg_color",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetFgColor,
-			libchafa,
-			"chafa_canvas_config_set_fg_color",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetBgColor,
-			libchafa,
-			"chafa_canvas_config_get_bg_color",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetBgColor,
-			libchafa,
-			"chafa_canvas_config_set_bg_color",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetWorkFactor,
-			libchafa,
-			"chafa_canvas_config_get_work_factor",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetWorkFactor,
-			libchafa,
-			"chafa_canvas_config_set_work_factor",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetDitherMode,
-			libchafa,
-			"chafa_canvas_config_get_dither_mode",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetDitherMode,
-			libchafa,
-			"chafa_canvas_config_set_dither_mode",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetDitherGrainSize,
-			libchafa,
-			"chafa_canvas_config_get_dither_grain_size",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetDitherGrainSize,
-			libchafa,
-			"chafa_canvas_config_set_dither_grain_size",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetDitherIntensity,
-			libchafa,
-			"chafa_canvas_config_get_dither_intensity",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetDitherIntensity,
-			libchafa,
-			"chafa_canvas_config_set_dither_intensity",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetOptimizations,
-			libchafa,
-			"chafa_canvas_config_get_optimizations",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetOptimizations,
-			libchafa,
-			"chafa_canvas_config_set_optimizations",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigGetPassthrough,
-			libchafa,
-			"chafa_canvas_config_get_passthrough",
-		)
-		purego.RegisterLibFunc(
-			&CanvasConfigSetPassthrough,
-			libchafa,
-			"chafa_canvas_config_set_passthrough",
-		)
+// bindUnsupported points the function variable at fptr to a stub which
+// stands in for the missing function name. It logs the first call, and
+// returns zero values.
+func bindUnsupported(fptr any, name string) {
+	fn := reflect.ValueOf(fptr).Elem()
+	var once sync.Once
+	stub := reflect.MakeFunc(fn.Type(), func(args []reflect.Value) []reflect.Value {
+		once.Do(func() {
+			logger().Warn("called a function missing from the loaded libchafa", "func", name, "version", libVersion)
+		})
+		return zeroResults(fn.Type())
+	})
+	fn.Set(stub)
+}
 
-		// Placement
-		purego.RegisterLibFunc(&PlacementNew, libchafa, "chafa_placement_new")
-		purego.RegisterLibFunc(&PlacementRef, libchafa, "chafa_placement_ref")
-		purego.RegisterLibFunc(&PlacementUnref, libchafa, "chafa_placement_unref")
-		purego.RegisterLibFunc(&PlacementGetTuck, libchafa, "chafa_placement_get_tuck")
-		purego.RegisterLibFunc(&PlacementSetTuck, libchafa, "chafa_placement_set_tuck")
-		purego.RegisterLibFunc(&PlacementGetHAlign, libchafa, "chafa_placement_get_halign")
-		purego.RegisterLibFunc(&PlacementSetHAlign, libchafa, "chafa_placement_set_halign")
-		purego.RegisterLibFunc(&PlacementGetVAlign, libchafa, "chafa_placement_get_valign")
-		purego.RegisterLibFunc(&PlacementSetVAlign, libchafa, "chafa_placement_set_valign")
+// requiredFuncs are the functions the package cannot do without. If any of
+// them is missing, the library is rejected by [Init]. Every other function
+// is optional, see [UnsupportedError].
+var requiredFuncs = map[string]bool{
+	"chafa_calc_canvas_geometry":          true,
+	"chafa_canvas_new":                    true,
+	"chafa_canvas_unref":                  true,
+	"chafa_canvas_draw_all_pixels":        true,
+	"chafa_canvas_print":                  true,
+	"chafa_canvas_config_new":             true,
+	"chafa_canvas_config_unref":           true,
+	"chafa_canvas_config_set_geometry":    true,
+	"chafa_canvas_config_set_canvas_mode": true,
+	"chafa_canvas_config_set_pixel_mode":  true,
+	"chafa_canvas_config_set_dither_mode": true,
+	"chafa_canvas_config_set_work_factor": true,
+	"chafa_canvas_config_set_symbol_map":  true,
+	"chafa_symbol_map_new":                true,
+	"chafa_symbol_map_unref":              true,
+	"chafa_symbol_map_add_by_tags":        true,
+	"chafa_symbol_map_apply_selectors":    true,
+}
 
-		// Image
-		purego.RegisterLibFunc(&ImageNew, libchafa, "chafa_image_new")
-		purego.RegisterLibFunc(&ImageRef, libchafa, "chafa_image_ref")
-		purego.RegisterLibFunc(&ImageUnref, libchafa, "chafa_image_unref")
-		purego.RegisterLibFunc(&ImageSetFrame, libchafa, "chafa_image_set_frame")
+// libraryFuncs lists every libchafa function bound by this package, along
+// with the exported (or wrapped) variable it is assigned to.
+var libraryFuncs = []libraryFunc{
+	// Canvas
+	{&CanvasNew, "chafa_canvas_new"},
+	{&CanvasNewSimilar, "chafa_canvas_new_similar"},
+	{&CanvasRef, "chafa_canvas_ref"},
+	{&CanvasUnRef, "chafa_canvas_unref"},
+	{&CanvasPeekConfig, "chafa_canvas_peek_config"},
+	{&CanvasSetPlacement, "chafa_canvas_set_placement"},
+	{&CanvasDrawAllPixels, "chafa_canvas_draw_all_pixels"},
+	{&CanvasPrint, "chafa_canvas_print"},
+	{&CanvasPrintRows, "chafa_canvas_print_rows"},
+	{&CanvasPrintRowsStrv, "chafa_canvas_print_rows_strv"},
+	{&CanvasGetCharAt, "chafa_canvas_get_char_at"},
+	{&CanvasSetCharAt, "chafa_canvas_set_char_at"},
+	{&CanvasGetColorsAt, "chafa_canvas_get_colors_at"},
+	{&CanvasSetColorsAt, "chafa_canvas_set_colors_at"},
+	{&CanvasGetRawColorsAt, "chafa_canvas_get_raw_colors_at"},
+	{&CanvasSetRawColorsAt, "chafa_canvas_set_raw_colors_at"},
 
-		// Frame
-		purego.RegisterLibFunc(&FrameNew, libchafa, "chafa_frame_new")
-		purego.RegisterLibFunc(&FrameNewBorrow, libchafa, "chafa_frame_new_borrow")
-		purego.RegisterLibFunc(&FrameNewSteal, libchafa, "chafa_frame_new_steal")
-		purego.RegisterLibFunc(&FrameRef, libchafa, "chafa_frame_ref")
-		purego.RegisterLibFunc(&FrameUnref, libchafa, "chafa_frame_unref")
+	// Config
+	{&CanvasConfigNew, "chafa_canvas_config_new"},
+	{&CanvasConfigCopy, "chafa_canvas_config_copy"},
+	{&CanvasConfigRef, "chafa_canvas_config_ref"},
+	{&CanvasConfigUnref, "chafa_canvas_config_unref"},
+	{&CanvasConfigGetGeometry, "chafa_canvas_config_get_geometry"},
+	{&CanvasConfigSetGeometry, "chafa_canvas_config_set_geometry"},
+	{&CanvasConfigGetCellGeometry, "chafa_canvas_config_get_cell_geometry"},
+	{&CanvasConfigSetCellGeometry, "chafa_canvas_config_set_cell_geometry"},
+	{&CanvasConfigGetPixelMode, "chafa_canvas_config_get_pixel_mode"},
+	{&CanvasConfigSetPixelMode, "chafa_canvas_config_set_pixel_mode"},
+	{&CanvasConfigGetCanvasMode, "chafa_canvas_config_get_canvas_mode"},
+	{&CanvasConfigSetCanvasMode, "chafa_canvas_config_set_canvas_mode"},
+	{&CanvasConfigGetColorExtractor, "chafa_canvas_config_get_color_extractor"},
+	{&CanvasConfigSetColorExtractor, "chafa_canvas_config_set_color_extractor"},
+	{&CanvasConfigGetColorSpace, "chafa_canvas_config_get_color_space"},
+	{&CanvasConfigSetColorSpace, "chafa_canvas_config_set_color_space"},
+	{&CanvasConfigGetPreprocessingEnabled, "chafa_canvas_config_get_preprocessing_enabled"},
+	{&CanvasConfigSetPreprocessingEnabled, "chafa_canvas_config_set_preprocessing_enabled"},
+	{&CanvasConfigPeekSymbolMap, "chafa_canvas_config_peek_symbol_map"},
+	{&CanvasConfigSetSymbolMap, "chafa_canvas_config_set_symbol_map"},
+	{&CanvasConfigPeekFillSymbolMap, "chafa_canvas_config_peek_fill_symbol_map"},
+	{&CanvasConfigGetTransparencyThreshold, "chafa_canvas_config_get_transparency_threshold"},
+	{&CanvasConfigSetTransparencyThreshold, "chafa_canvas_config_set_transparency_threshold"},
+	{&CanvasConfigGetFgOnlyEnabled, "chafa_canvas_config_get_fg_only_enabled"},
+	{&CanvasConfigSetFgOnlyEnabled, "chafa_canvas_config_set_fg_only_enabled"},
+	{&CanvasConfigGetFgColor, "chafa_canvas_config_get_fg_color"},
+	{&CanvasConfigSetFgColor, "chafa_canvas_config_set_fg_color"},
+	{&CanvasConfigGetBgColor, "chafa_canvas_config_get_bg_color"},
+	{&CanvasConfigSetBgColor, "chafa_canvas_config_set_bg_color"},
+	{&CanvasConfigGetWorkFactor, "chafa_canvas_config_get_work_factor"},
+	{&CanvasConfigSetWorkFactor, "chafa_canvas_config_set_work_factor"},
+	{&CanvasConfigGetDitherMode, "chafa_canvas_config_get_dither_mode"},
+	{&CanvasConfigSetDitherMode, "chafa_canvas_config_set_dither_mode"},
+	{&CanvasConfigGetDitherGrainSize, "chafa_canvas_config_get_dither_grain_size"},
+	{&CanvasConfigSetDitherGrainSize, "chafa_canvas_config_set_dither_grain_size"},
+	{&CanvasConfigGetDitherIntensity, "chafa_canvas_config_get_dither_intensity"},
+	{&CanvasConfigSetDitherIntensity, "chafa_canvas_config_set_dither_intensity"},
+	{&CanvasConfigGetOptimizations, "chafa_canvas_config_get_optimizations"},
+	{&CanvasConfigSetOptimizations, "chafa_canvas_config_set_optimizations"},
+	{&CanvasConfigGetPassthrough, "chafa_canvas_config_get_passthrough"},
+	{&CanvasConfigSetPassthrough, "chafa_canvas_config_set_passthrough"},
 
-		// SymbolMap
-		purego.RegisterLibFunc(&SymbolMapNew, libchafa, "chafa_symbol_map_new")
-		purego.RegisterLibFunc(&SymbolMapCopy, libchafa, "chafa_symbol_map_copy")
-		purego.RegisterLibFunc(&SymbolMapRef, libchafa, "chafa_symbol_map_ref")
-		purego.RegisterLibFunc(&SymbolMapUnref, libchafa, "chafa_symbol_map_unref")
-		purego.RegisterLibFunc(&SymbolMapAddByTags, libchafa, "chafa_symbol_map_add_by_tags")
-		purego.RegisterLibFunc(&SymbolMapAddByRange, libchafa, "chafa_symbol_map_add_by_range")
-		purego.RegisterLibFunc(&SymbolMapRemoveByTags, libchafa, "chafa_symbol_map_remove_by_tags")
-		purego.RegisterLibFunc(
-			&SymbolMapRemoveByRange,
-			libchafa,
-			"chafa_symbol_map_remove_by_range",
-		)
-		purego.RegisterLibFunc(
-			&SymbolMapApplySelectors,
-			libchafa,
-			"chafa_symbol_map_apply_selectors",
-		)
-		purego.RegisterLibFunc(
-			&SymbolMapGetAllowBuiltinGlyphs,
-			libchafa,
-			"chafa_symbol_map_get_allow_builtin_glyphs",
-		)
-		purego.RegisterLibFunc(
-			&SymbolMapSetAllowBuiltinGlyphs,
-			libchafa,
-			"chafa_symbol_map_set_allow_builtin_glyphs",
-		)
-		purego.RegisterLibFunc(&SymbolMapGetGlyph, libchafa, "chafa_symbol_map_get_glyph")
-		purego.RegisterLibFunc(&SymbolMapAddGlyph, libchafa, "chafa_symbol_map_add_glyph")
+	// Placement
+	{&PlacementNew, "chafa_placement_new"},
+	{&PlacementRef, "chafa_placement_ref"},
+	{&PlacementUnref, "chafa_placement_unref"},
+	{&PlacementGetTuck, "chafa_placement_get_tuck"},
+	{&PlacementSetTuck, "chafa_placement_set_tuck"},
+	{&PlacementGetHAlign, "chafa_placement_get_halign"},
+	{&PlacementSetHAlign, "chafa_placement_set_halign"},
+	{&PlacementGetVAlign, "chafa_placement_get_valign"},
+	{&PlacementSetVAlign, "chafa_placement_set_valign"},
 
-		// TermDb
-		purego.RegisterLibFunc(&TermDbNew, libchafa, "chafa_term_db_new")
-		purego.RegisterLibFunc(&TermDbCopy, libchafa, "chafa_term_db_copy")
-		purego.RegisterLibFunc(&TermDbRef, libchafa, "chafa_term_db_ref")
-		purego.RegisterLibFunc(&TermDbUnref, libchafa, "chafa_term_db_unref")
-		purego.RegisterLibFunc(&TermDbGetDefault, libchafa, "chafa_term_db_get_default")
-		purego.RegisterLibFunc(&termDbDetect, libchafa, "chafa_term_db_detect")
-		purego.RegisterLibFunc(&TermDbGetFallbackInfo, libchafa, "chafa_term_db_get_fallback_info")
+	// Image
+	{&ImageNew, "chafa_image_new"},
+	{&ImageRef, "chafa_image_ref"},
+	{&ImageUnref, "chafa_image_unref"},
+	{&ImageSetFrame, "chafa_image_set_frame"},
 
-		// TermInfo
-		purego.RegisterLibFunc(&TermInfoNew, libchafa, "chafa_term_info_new")
-		purego.RegisterLibFunc(&TermInfoCopy, libchafa, "chafa_term_info_copy")
-		purego.RegisterLibFunc(&TermInfoRef, libchafa, "chafa_term_info_ref")
-		purego.RegisterLibFunc(&TermInfoUnref, libchafa, "chafa_term_info_unref")
-		purego.RegisterLibFunc(&TermInfoChain, libchafa, "chafa_term_info_chain")
-		purego.RegisterLibFunc(&TermInfoSupplement, libchafa, "chafa_term_info_supplement")
-		purego.RegisterLibFunc(&TermInfoGetName, libchafa, "chafa_term_info_get_name")
-		purego.RegisterLibFunc(&TermInfoSetName, libchafa, "chafa_term_info_set_name")
-		purego.RegisterLibFunc(&TermInfoGetQuirks, libchafa, "chafa_term_info_get_quirks")
-		purego.RegisterLibFunc(&TermInfoSetQuirks, libchafa, "chafa_term_info_set_quirks")
-		purego.RegisterLibFunc(
-			&TermInfoGetSafeSymbolTags,
-			libchafa,
-			"chafa_term_info_get_safe_symbol_tags",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoSetSafeSymbolTags,
-			libchafa,
-			"chafa_term_info_set_safe_symbol_tags",
-		)
-		purego.RegisterLibFunc(&TermInfoGetSeq, libchafa, "chafa_term_info_get_seq")
-		purego.RegisterLibFunc(&TermInfoSetSeq, libchafa, "chafa_term_info_set_seq")
-		purego.RegisterLibFunc(&TermInfoHaveSeq, libchafa, "chafa_term_info_have_seq")
-		purego.RegisterLibFunc(&TermInfoGetInheritSeq, libchafa, "chafa_term_info_get_inherit_seq")
-		purego.RegisterLibFunc(&TermInfoSetInheritSeq, libchafa, "chafa_term_info_set_inherit_seq")
-		purego.RegisterLibFunc(&TermInfoEmitSeq, libchafa, "chafa_term_info_emit_seq")
-		purego.RegisterLibFunc(&TermInfoEmitSeqValist, libchafa, "chafa_term_info_emit_seq_valist")
-		purego.RegisterLibFunc(&TermInfoParseSeq, libchafa, "chafa_term_info_parse_seq")
-		purego.RegisterLibFunc(
-			&TermInfoParseSeqVarargs,
-			libchafa,
-			"chafa_term_info_parse_seq_varargs",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoIsCanvasModeSupported,
-			libchafa,
-			"chafa_term_info_is_canvas_mode_supported",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoGetBestCanvasMode,
-			libchafa,
-			"chafa_term_info_get_best_canvas_mode",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoIsPixelModeSupported,
-			libchafa,
-			"chafa_term_info_is_pixel_mode_supported",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoGetBestPixelMode,
-			libchafa,
-			"chafa_term_info_get_best_pixel_mode",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoGetIsPixelPassthroughNeeded,
-			libchafa,
-			"chafa_term_info_get_is_pixel_passthrough_needed",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoSetIsPixelPassthroughNeeded,
-			libchafa,
-			"chafa_term_info_set_is_pixel_passthrough_needed",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoGetPassthroughType,
-			libchafa,
-			"chafa_term_info_get_passthrough_type",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetTerminalSoft,
-			libchafa,
-			"chafa_term_info_emit_reset_terminal_soft",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetTerminalHard,
-			libchafa,
-			"chafa_term_info_emit_reset_terminal_hard",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetAttributes,
-			libchafa,
-			"chafa_term_info_emit_reset_attributes",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitClear, libchafa, "chafa_term_info_emit_clear")
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorToPos,
-			libchafa,
-			"chafa_term_info_emit_cursor_to_pos",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorToTopLeft,
-			libchafa,
-			"chafa_term_info_emit_cursor_to_top_left",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorToBottomLeft,
-			libchafa,
-			"chafa_term_info_emit_cursor_to_bottom_left",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitCursorUp, libchafa, "chafa_term_info_emit_cursor_up")
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorDown,
-			libchafa,
-			"chafa_term_info_emit_cursor_down",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorLeft,
-			libchafa,
-			"chafa_term_info_emit_cursor_left",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorRight,
-			libchafa,
-			"chafa_term_info_emit_cursor_right",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitCursorUp1, libchafa, "chafa_term_info_emit_cursor_up_1")
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorDown1,
-			libchafa,
-			"chafa_term_info_emit_cursor_down_1",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorLeft1,
-			libchafa,
-			"chafa_term_info_emit_cursor_left_1",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorRight1,
-			libchafa,
-			"chafa_term_info_emit_cursor_right_1",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorUpScroll,
-			libchafa,
-			"chafa_term_info_emit_cursor_up_scroll",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCursorDownScroll,
-			libchafa,
-			"chafa_term_info_emit_cursor_down_scroll",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitInsertCells,
-			libchafa,
-			"chafa_term_info_emit_insert_cells",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDeleteCells,
-			libchafa,
-			"chafa_term_info_emit_delete_cells",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitInsertRows,
-			libchafa,
-			"chafa_term_info_emit_insert_rows",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDeleteRows,
-			libchafa,
-			"chafa_term_info_emit_delete_rows",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableCursor,
-			libchafa,
-			"chafa_term_info_emit_enable_cursor",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDisableCursor,
-			libchafa,
-			"chafa_term_info_emit_disable_cursor",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableEcho,
-			libchafa,
-			"chafa_term_info_emit_enable_echo",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDisableEcho,
-			libchafa,
-			"chafa_term_info_emit_disable_echo",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableInsert,
-			libchafa,
-			"chafa_term_info_emit_enable_insert",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDisableInsert,
-			libchafa,
-			"chafa_term_info_emit_disable_insert",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableWrap,
-			libchafa,
-			"chafa_term_info_emit_enable_wrap",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDisableWrap,
-			libchafa,
-			"chafa_term_info_emit_disable_wrap",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableBold,
-			libchafa,
-			"chafa_term_info_emit_enable_bold",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitInvertColors,
-			libchafa,
-			"chafa_term_info_emit_invert_colors",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorBg8,
-			libchafa,
-			"chafa_term_info_emit_set_color_bg_8",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFg8,
-			libchafa,
-			"chafa_term_info_emit_set_color_fg_8",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFgbg8,
-			libchafa,
-			"chafa_term_info_emit_set_color_fgbg_8",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFg16,
-			libchafa,
-			"chafa_term_info_emit_set_color_fg_16",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorBg16,
-			libchafa,
-			"chafa_term_info_emit_set_color_bg_16",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFgbg16,
-			libchafa,
-			"chafa_term_info_emit_set_color_fgbg_16",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFg256,
-			libchafa,
-			"chafa_term_info_emit_set_color_fg_256",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorBg256,
-			libchafa,
-			"chafa_term_info_emit_set_color_bg_256",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFgbg256,
-			libchafa,
-			"chafa_term_info_emit_set_color_fgbg_256",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFgDirect,
-			libchafa,
-			"chafa_term_info_emit_set_color_fg_direct",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorBgDirect,
-			libchafa,
-			"chafa_term_info_emit_set_color_bg_direct",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetColorFgbgDirect,
-			libchafa,
-			"chafa_term_info_emit_set_color_fgbg_direct",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetColorFg,
-			libchafa,
-			"chafa_term_info_emit_reset_color_fg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetColorBg,
-			libchafa,
-			"chafa_term_info_emit_reset_color_bg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetColorFgbg,
-			libchafa,
-			"chafa_term_info_emit_reset_color_fgbg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetDefaultFg,
-			libchafa,
-			"chafa_term_info_emit_set_default_fg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetDefaultBg,
-			libchafa,
-			"chafa_term_info_emit_set_default_bg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetDefaultFg,
-			libchafa,
-			"chafa_term_info_emit_reset_default_fg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetDefaultBg,
-			libchafa,
-			"chafa_term_info_emit_reset_default_bg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitQueryDefaultFg,
-			libchafa,
-			"chafa_term_info_emit_query_default_fg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitQueryDefaultBg,
-			libchafa,
-			"chafa_term_info_emit_query_default_bg",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitQueryPrimaryDeviceAttributes,
-			libchafa,
-			"chafa_term_info_emit_query_primary_device_attributes",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitPrimaryDeviceAttributes,
-			libchafa,
-			"chafa_term_info_emit_primary_device_attributes",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitQueryCellSizePx,
-			libchafa,
-			"chafa_term_info_emit_query_cell_size_px",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitCellSizePx,
-			libchafa,
-			"chafa_term_info_emit_cell_size_px",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitQueryTextAreaSizeCells,
-			libchafa,
-			"chafa_term_info_emit_query_text_area_size_cells",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitTextAreaSizeCells,
-			libchafa,
-			"chafa_term_info_emit_text_area_size_cells",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitQueryTextAreaSizePx,
-			libchafa,
-			"chafa_term_info_emit_query_text_area_size_px",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitTextAreaSizePx,
-			libchafa,
-			"chafa_term_info_emit_text_area_size_px",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitRepeatChar,
-			libchafa,
-			"chafa_term_info_emit_repeat_char",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetScrollingRows,
-			libchafa,
-			"chafa_term_info_emit_set_scrolling_rows",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitResetScrollingRows,
-			libchafa,
-			"chafa_term_info_emit_reset_scrolling_rows",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSaveCursorPos,
-			libchafa,
-			"chafa_term_info_emit_save_cursor_pos",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitRestoreCursorPos,
-			libchafa,
-			"chafa_term_info_emit_restore_cursor_pos",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginSixels,
-			libchafa,
-			"chafa_term_info_emit_begin_sixels",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitEndSixels, libchafa, "chafa_term_info_emit_end_sixels")
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableSixelScrolling,
-			libchafa,
-			"chafa_term_info_emit_enable_sixel_scrolling",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDisableSixelScrolling,
-			libchafa,
-			"chafa_term_info_emit_disable_sixel_scrolling",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetSixelAdvanceDown,
-			libchafa,
-			"chafa_term_info_emit_set_sixel_advance_down",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitSetSixelAdvanceRight,
-			libchafa,
-			"chafa_term_info_emit_set_sixel_advance_right",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginKittyImmediateImageV1,
-			libchafa,
-			"chafa_term_info_emit_begin_kitty_immediate_image_v1",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginKittyImmediateVirtImageV1,
-			libchafa,
-			"chafa_term_info_emit_begin_kitty_immediate_virt_image_v1",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndKittyImage,
-			libchafa,
-			"chafa_term_info_emit_end_kitty_image",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginKittyImageChunk,
-			libchafa,
-			"chafa_term_info_emit_begin_kitty_image_chunk",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndKittyImageChunk,
-			libchafa,
-			"chafa_term_info_emit_end_kitty_image_chunk",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginIterm2Image,
-			libchafa,
-			"chafa_term_info_emit_begin_iterm2_image",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndIterm2Image,
-			libchafa,
-			"chafa_term_info_emit_end_iterm2_image",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginScreenPassthrough,
-			libchafa,
-			"chafa_term_info_emit_begin_screen_passthrough",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndScreenPassthrough,
-			libchafa,
-			"chafa_term_info_emit_end_screen_passthrough",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEnableAltScreen,
-			libchafa,
-			"chafa_term_info_emit_enable_alt_screen",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDisableAltScreen,
-			libchafa,
-			"chafa_term_info_emit_disable_alt_screen",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitBeginTmuxPassthrough,
-			libchafa,
-			"chafa_term_info_emit_begin_tmux_passthrough",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndTmuxPassthrough,
-			libchafa,
-			"chafa_term_info_emit_end_tmux_passthrough",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitReturnKey, libchafa, "chafa_term_info_emit_return_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitBackspaceKey,
-			libchafa,
-			"chafa_term_info_emit_backspace_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitDeleteKey, libchafa, "chafa_term_info_emit_delete_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitDeleteCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_delete_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDeleteShiftKey,
-			libchafa,
-			"chafa_term_info_emit_delete_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitInsertKey, libchafa, "chafa_term_info_emit_insert_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitInsertCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_insert_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitInsertShiftKey,
-			libchafa,
-			"chafa_term_info_emit_insert_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitHomeKey, libchafa, "chafa_term_info_emit_home_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitHomeCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_home_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitHomeShiftKey,
-			libchafa,
-			"chafa_term_info_emit_home_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitEndKey, libchafa, "chafa_term_info_emit_end_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_end_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitEndShiftKey,
-			libchafa,
-			"chafa_term_info_emit_end_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitUpKey, libchafa, "chafa_term_info_emit_up_key")
-		purego.RegisterLibFunc(&TermInfoEmitUpCtrlKey, libchafa, "chafa_term_info_emit_up_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitUpShiftKey,
-			libchafa,
-			"chafa_term_info_emit_up_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitDownKey, libchafa, "chafa_term_info_emit_down_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitDownCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_down_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitDownShiftKey,
-			libchafa,
-			"chafa_term_info_emit_down_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitLeftKey, libchafa, "chafa_term_info_emit_left_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitLeftCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_left_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitLeftShiftKey,
-			libchafa,
-			"chafa_term_info_emit_left_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitRightKey, libchafa, "chafa_term_info_emit_right_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitRightCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_right_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitRightShiftKey,
-			libchafa,
-			"chafa_term_info_emit_right_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitPageUpKey, libchafa, "chafa_term_info_emit_page_up_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitPageUpCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_page_up_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitPageUpShiftKey,
-			libchafa,
-			"chafa_term_info_emit_page_up_shift_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitPageDownKey,
-			libchafa,
-			"chafa_term_info_emit_page_down_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitPageDownCtrlKey,
-			libchafa,
-			"chafa_term_info_emit_page_down_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitPageDownShiftKey,
-			libchafa,
-			"chafa_term_info_emit_page_down_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitTabKey, libchafa, "chafa_term_info_emit_tab_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitTabShiftKey,
-			libchafa,
-			"chafa_term_info_emit_tab_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF1Key, libchafa, "chafa_term_info_emit_f1_key")
-		purego.RegisterLibFunc(&TermInfoEmitF1CtrlKey, libchafa, "chafa_term_info_emit_f1_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF1ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f1_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF2Key, libchafa, "chafa_term_info_emit_f2_key")
-		purego.RegisterLibFunc(&TermInfoEmitF2CtrlKey, libchafa, "chafa_term_info_emit_f2_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF2ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f2_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF3Key, libchafa, "chafa_term_info_emit_f3_key")
-		purego.RegisterLibFunc(&TermInfoEmitF3CtrlKey, libchafa, "chafa_term_info_emit_f3_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF3ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f3_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF4Key, libchafa, "chafa_term_info_emit_f4_key")
-		purego.RegisterLibFunc(&TermInfoEmitF4CtrlKey, libchafa, "chafa_term_info_emit_f4_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF4ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f4_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF5Key, libchafa, "chafa_term_info_emit_f5_key")
-		purego.RegisterLibFunc(&TermInfoEmitF5CtrlKey, libchafa, "chafa_term_info_emit_f5_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF5ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f5_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF6Key, libchafa, "chafa_term_info_emit_f6_key")
-		purego.RegisterLibFunc(&TermInfoEmitF6CtrlKey, libchafa, "chafa_term_info_emit_f6_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF6ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f6_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF7Key, libchafa, "chafa_term_info_emit_f7_key")
-		purego.RegisterLibFunc(&TermInfoEmitF7CtrlKey, libchafa, "chafa_term_info_emit_f7_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF7ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f7_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF8Key, libchafa, "chafa_term_info_emit_f8_key")
-		purego.RegisterLibFunc(&TermInfoEmitF8CtrlKey, libchafa, "chafa_term_info_emit_f8_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF8ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f8_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF9Key, libchafa, "chafa_term_info_emit_f9_key")
-		purego.RegisterLibFunc(&TermInfoEmitF9CtrlKey, libchafa, "chafa_term_info_emit_f9_ctrl_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF9ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f9_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF10Key, libchafa, "chafa_term_info_emit_f10_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF10CtrlKey,
-			libchafa,
-			"chafa_term_info_emit_f10_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitF10ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f10_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF11Key, libchafa, "chafa_term_info_emit_f11_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF11CtrlKey,
-			libchafa,
-			"chafa_term_info_emit_f11_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitF11ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f11_shift_key",
-		)
-		purego.RegisterLibFunc(&TermInfoEmitF12Key, libchafa, "chafa_term_info_emit_f12_key")
-		purego.RegisterLibFunc(
-			&TermInfoEmitF12CtrlKey,
-			libchafa,
-			"chafa_term_info_emit_f12_ctrl_key",
-		)
-		purego.RegisterLibFunc(
-			&TermInfoEmitF12ShiftKey,
-			libchafa,
-			"chafa_term_info_emit_f12_shift_key",
-		)
+	// Frame
+	{&FrameNew, "chafa_frame_new"},
+	{&FrameNewBorrow, "chafa_frame_new_borrow"},
+	{&FrameNewSteal, "chafa_frame_new_steal"},
+	{&FrameRef, "chafa_frame_ref"},
+	{&FrameUnref, "chafa_frame_unref"},
 
-		// Features
-		purego.RegisterLibFunc(&GetBuiltinFeatures, libchafa, "chafa_get_builtin_features")
-		purego.RegisterLibFunc(&GetSupportedFeatures, libchafa, "chafa_get_supported_features")
-		purego.RegisterLibFunc(&DescribeFeatures, libchafa, "chafa_describe_features")
-		purego.RegisterLibFunc(&GetNThreads, libchafa, "chafa_get_n_threads")
-		purego.RegisterLibFunc(&SetNThreads, libchafa, "chafa_set_n_threads")
-		purego.RegisterLibFunc(&GetNActualThreads, libchafa, "chafa_get_n_actual_threads")
+	// SymbolMap
+	{&SymbolMapNew, "chafa_symbol_map_new"},
+	{&SymbolMapCopy, "chafa_symbol_map_copy"},
+	{&SymbolMapRef, "chafa_symbol_map_ref"},
+	{&SymbolMapUnref, "chafa_symbol_map_unref"},
+	{&SymbolMapAddByTags, "chafa_symbol_map_add_by_tags"},
+	{&SymbolMapAddByRange, "chafa_symbol_map_add_by_range"},
+	{&SymbolMapRemoveByTags, "chafa_symbol_map_remove_by_tags"},
+	{&SymbolMapRemoveByRange, "chafa_symbol_map_remove_by_range"},
+	{&symbolMapApplySelectors, "chafa_symbol_map_apply_selectors"},
+	{&SymbolMapGetAllowBuiltinGlyphs, "chafa_symbol_map_get_allow_builtin_glyphs"},
+	{&SymbolMapSetAllowBuiltinGlyphs, "chafa_symbol_map_set_allow_builtin_glyphs"},
+	{&SymbolMapGetGlyph, "chafa_symbol_map_get_glyph"},
+	{&SymbolMapAddGlyph, "chafa_symbol_map_add_glyph"},
 
-		// Miscellaneous
-		purego.RegisterLibFunc(&CalcCanvasGeometry, libchafa, "chafa_calc_canvas_geometry")
-	})
+	// TermDb
+	{&TermDbNew, "chafa_term_db_new"},
+	{&TermDbCopy, "chafa_term_db_copy"},
+	{&TermDbRef, "chafa_term_db_ref"},
+	{&TermDbUnref, "chafa_term_db_unref"},
+	{&TermDbGetDefault, "chafa_term_db_get_default"},
+	{&termDbDetect, "chafa_term_db_detect"},
+	{&TermDbGetFallbackInfo, "chafa_term_db_get_fallback_info"},
+
+	// TermInfo
+	{&TermInfoNew, "chafa_term_info_new"},
+	{&TermInfoCopy, "chafa_term_info_copy"},
+	{&TermInfoRef, "chafa_term_info_ref"},
+	{&TermInfoUnref, "chafa_term_info_unref"},
+	{&TermInfoChain, "chafa_term_info_chain"},
+	{&TermInfoSupplement, "chafa_term_info_supplement"},
+	{&TermInfoGetName, "chafa_term_info_get_name"},
+	{&TermInfoSetName, "chafa_term_info_set_name"},
+	{&TermInfoGetQuirks, "chafa_term_info_get_quirks"},
+	{&TermInfoSetQuirks, "chafa_term_info_set_quirks"},
+	{&TermInfoGetSafeSymbolTags, "chafa_term_info_get_safe_symbol_tags"},
+	{&TermInfoSetSafeSymbolTags, "chafa_term_info_set_safe_symbol_tags"},
+	{&TermInfoGetSeq, "chafa_term_info_get_seq"},
+	{&termInfoSetSeq, "chafa_term_info_set_seq"},
+	{&TermInfoErrorQuark, "chafa_term_info_error_quark"},
+	{&TermInfoHaveSeq, "chafa_term_info_have_seq"},
+	{&TermInfoGetInheritSeq, "chafa_term_info_get_inherit_seq"},
+	{&TermInfoSetInheritSeq, "chafa_term_info_set_inherit_seq"},
+	{&TermInfoEmitSeq, "chafa_term_info_emit_seq"},
+	{&TermInfoEmitSeqValist, "chafa_term_info_emit_seq_valist"},
+	{&TermInfoParseSeq, "chafa_term_info_parse_seq"},
+	{&TermInfoParseSeqVarargs, "chafa_term_info_parse_seq_varargs"},
+	{&TermInfoIsCanvasModeSupported, "chafa_term_info_is_canvas_mode_supported"},
+	{&TermInfoGetBestCanvasMode, "chafa_term_info_get_best_canvas_mode"},
+	{&TermInfoIsPixelModeSupported, "chafa_term_info_is_pixel_mode_supported"},
+	{&TermInfoGetBestPixelMode, "chafa_term_info_get_best_pixel_mode"},
+	{&TermInfoGetIsPixelPassthroughNeeded, "chafa_term_info_get_is_pixel_passthrough_needed"},
+	{&TermInfoSetIsPixelPassthroughNeeded, "chafa_term_info_set_is_pixel_passthrough_needed"},
+	{&TermInfoGetPassthroughType, "chafa_term_info_get_passthrough_type"},
+	{&TermInfoEmitResetTerminalSoft, "chafa_term_info_emit_reset_terminal_soft"},
+	{&TermInfoEmitResetTerminalHard, "chafa_term_info_emit_reset_terminal_hard"},
+	{&TermInfoEmitResetAttributes, "chafa_term_info_emit_reset_attributes"},
+	{&TermInfoEmitClear, "chafa_term_info_emit_clear"},
+	{&TermInfoEmitCursorToPos, "chafa_term_info_emit_cursor_to_pos"},
+	{&TermInfoEmitCursorToTopLeft, "chafa_term_info_emit_cursor_to_top_left"},
+	{&TermInfoEmitCursorToBottomLeft, "chafa_term_info_emit_cursor_to_bottom_left"},
+	{&TermInfoEmitCursorUp, "chafa_term_info_emit_cursor_up"},
+	{&TermInfoEmitCursorDown, "chafa_term_info_emit_cursor_down"},
+	{&TermInfoEmitCursorLeft, "chafa_term_info_emit_cursor_left"},
+	{&TermInfoEmitCursorRight, "chafa_term_info_emit_cursor_right"},
+	{&TermInfoEmitCursorUp1, "chafa_term_info_emit_cursor_up_1"},
+	{&TermInfoEmitCursorDown1, "chafa_term_info_emit_cursor_down_1"},
+	{&TermInfoEmitCursorLeft1, "chafa_term_info_emit_cursor_left_1"},
+	{&TermInfoEmitCursorRight1, "chafa_term_info_emit_cursor_right_1"},
+	{&TermInfoEmitCursorUpScroll, "chafa_term_info_emit_cursor_up_scroll"},
+	{&TermInfoEmitCursorDownScroll, "chafa_term_info_emit_cursor_down_scroll"},
+	{&TermInfoEmitInsertCells, "chafa_term_info_emit_insert_cells"},
+	{&TermInfoEmitDeleteCells, "chafa_term_info_emit_delete_cells"},
+	{&TermInfoEmitInsertRows, "chafa_term_info_emit_insert_rows"},
+	{&TermInfoEmitDeleteRows, "chafa_term_info_emit_delete_rows"},
+	{&TermInfoEmitEnableCursor, "chafa_term_info_emit_enable_cursor"},
+	{&TermInfoEmitDisableCursor, "chafa_term_info_emit_disable_cursor"},
+	{&TermInfoEmitEnableEcho, "chafa_term_info_emit_enable_echo"},
+	{&TermInfoEmitDisableEcho, "chafa_term_info_emit_disable_echo"},
+	{&TermInfoEmitEnableInsert, "chafa_term_info_emit_enable_insert"},
+	{&TermInfoEmitDisableInsert, "chafa_term_info_emit_disable_insert"},
+	{&TermInfoEmitEnableWrap, "chafa_term_info_emit_enable_wrap"},
+	{&TermInfoEmitDisableWrap, "chafa_term_info_emit_disable_wrap"},
+	{&TermInfoEmitEnableBold, "chafa_term_info_emit_enable_bold"},
+	{&TermInfoEmitInvertColors, "chafa_term_info_emit_invert_colors"},
+	{&TermInfoEmitSetColorBg8, "chafa_term_info_emit_set_color_bg_8"},
+	{&TermInfoEmitSetColorFg8, "chafa_term_info_emit_set_color_fg_8"},
+	{&TermInfoEmitSetColorFgbg8, "chafa_term_info_emit_set_color_fgbg_8"},
+	{&TermInfoEmitSetColorFg16, "chafa_term_info_emit_set_color_fg_16"},
+	{&TermInfoEmitSetColorBg16, "chafa_term_info_emit_set_color_bg_16"},
+	{&TermInfoEmitSetColorFgbg16, "chafa_term_info_emit_set_color_fgbg_16"},
+	{&TermInfoEmitSetColorFg256, "chafa_term_info_emit_set_color_fg_256"},
+	{&TermInfoEmitSetColorBg256, "chafa_term_info_emit_set_color_bg_256"},
+	{&TermInfoEmitSetColorFgbg256, "chafa_term_info_emit_set_color_fgbg_256"},
+	{&TermInfoEmitSetColorFgDirect, "chafa_term_info_emit_set_color_fg_direct"},
+	{&TermInfoEmitSetColorBgDirect, "chafa_term_info_emit_set_color_bg_direct"},
+	{&TermInfoEmitSetColorFgbgDirect, "chafa_term_info_emit_set_color_fgbg_direct"},
+	{&TermInfoEmitResetColorFg, "chafa_term_info_emit_reset_color_fg"},
+	{&TermInfoEmitResetColorBg, "chafa_term_info_emit_reset_color_bg"},
+	{&TermInfoEmitResetColorFgbg, "chafa_term_info_emit_reset_color_fgbg"},
+	{&TermInfoEmitSetDefaultFg, "chafa_term_info_emit_set_default_fg"},
+	{&TermInfoEmitSetDefaultBg, "chafa_term_info_emit_set_default_bg"},
+	{&TermInfoEmitResetDefaultFg, "chafa_term_info_emit_reset_default_fg"},
+	{&TermInfoEmitResetDefaultBg, "chafa_term_info_emit_reset_default_bg"},
+	{&TermInfoEmitQueryDefaultFg, "chafa_term_info_emit_query_default_fg"},
+	{&TermInfoEmitQueryDefaultBg, "chafa_term_info_emit_query_default_bg"},
+	{&TermInfoEmitQueryPrimaryDeviceAttributes, "chafa_term_info_emit_query_primary_device_attributes"},
+	{&TermInfoEmitPrimaryDeviceAttributes, "chafa_term_info_emit_primary_device_attributes"},
+	{&TermInfoEmitQueryCellSizePx, "chafa_term_info_emit_query_cell_size_px"},
+	{&TermInfoEmitCellSizePx, "chafa_term_info_emit_cell_size_px"},
+	{&TermInfoEmitQueryTextAreaSizeCells, "chafa_term_info_emit_query_text_area_size_cells"},
+	{&TermInfoEmitTextAreaSizeCells, "chafa_term_info_emit_text_area_size_cells"},
+	{&TermInfoEmitQueryTextAreaSizePx, "chafa_term_info_emit_query_text_area_size_px"},
+	{&TermInfoEmitTextAreaSizePx, "chafa_term_info_emit_text_area_size_px"},
+	{&TermInfoEmitRepeatChar, "chafa_term_info_emit_repeat_char"},
+	{&TermInfoEmitSetScrollingRows, "chafa_term_info_emit_set_scrolling_rows"},
+	{&TermInfoEmitResetScrollingRows, "chafa_term_info_emit_reset_scrolling_rows"},
+	{&TermInfoEmitSaveCursorPos, "chafa_term_info_emit_save_cursor_pos"},
+	{&TermInfoEmitRestoreCursorPos, "chafa_term_info_emit_restore_cursor_pos"},
+	{&TermInfoEmitBeginSixels, "chafa_term_info_emit_begin_sixels"},
+	{&TermInfoEmitEndSixels, "chafa_term_info_emit_end_sixels"},
+	{&TermInfoEmitEnableSixelScrolling, "chafa_term_info_emit_enable_sixel_scrolling"},
+	{&TermInfoEmitDisableSixelScrolling, "chafa_term_info_emit_disable_sixel_scrolling"},
+	{&TermInfoEmitSetSixelAdvanceDown, "chafa_term_info_emit_set_sixel_advance_down"},
+	{&TermInfoEmitSetSixelAdvanceRight, "chafa_term_info_emit_set_sixel_advance_right"},
+	{&TermInfoEmitBeginKittyImmediateImageV1, "chafa_term_info_emit_begin_kitty_immediate_image_v1"},
+	{&TermInfoEmitBeginKittyImmediateVirtImageV1, "chafa_term_info_emit_begin_kitty_immediate_virt_image_v1"},
+	{&TermInfoEmitEndKittyImage, "chafa_term_info_emit_end_kitty_image"},
+	{&TermInfoEmitBeginKittyImageChunk, "chafa_term_info_emit_begin_kitty_image_chunk"},
+	{&TermInfoEmitEndKittyImageChunk, "chafa_term_info_emit_end_kitty_image_chunk"},
+	{&TermInfoEmitBeginIterm2Image, "chafa_term_info_emit_begin_iterm2_image"},
+	{&TermInfoEmitEndIterm2Image, "chafa_term_info_emit_end_iterm2_image"},
+	{&TermInfoEmitBeginScreenPassthrough, "chafa_term_info_emit_begin_screen_passthrough"},
+	{&TermInfoEmitEndScreenPassthrough, "chafa_term_info_emit_end_screen_passthrough"},
+	{&TermInfoEmitEnableAltScreen, "chafa_term_info_emit_enable_alt_screen"},
+	{&TermInfoEmitDisableAltScreen, "chafa_term_info_emit_disable_alt_screen"},
+	{&TermInfoEmitBeginTmuxPassthrough, "chafa_term_info_emit_begin_tmux_passthrough"},
+	{&TermInfoEmitEndTmuxPassthrough, "chafa_term_info_emit_end_tmux_passthrough"},
+	{&TermInfoEmitReturnKey, "chafa_term_info_emit_return_key"},
+	{&TermInfoEmitBackspaceKey, "chafa_term_info_emit_backspace_key"},
+	{&TermInfoEmitDeleteKey, "chafa_term_info_emit_delete_key"},
+	{&TermInfoEmitDeleteCtrlKey, "chafa_term_info_emit_delete_ctrl_key"},
+	{&TermInfoEmitDeleteShiftKey, "chafa_term_info_emit_delete_shift_key"},
+	{&TermInfoEmitInsertKey, "chafa_term_info_emit_insert_key"},
+	{&TermInfoEmitInsertCtrlKey, "chafa_term_info_emit_insert_ctrl_key"},
+	{&TermInfoEmitInsertShiftKey, "chafa_term_info_emit_insert_shift_key"},
+	{&TermInfoEmitHomeKey, "chafa_term_info_emit_home_key"},
+	{&TermInfoEmitHomeCtrlKey, "chafa_term_info_emit_home_ctrl_key"},
+	{&TermInfoEmitHomeShiftKey, "chafa_term_info_emit_home_shift_key"},
+	{&TermInfoEmitEndKey, "chafa_term_info_emit_end_key"},
+	{&TermInfoEmitEndCtrlKey, "chafa_term_info_emit_end_ctrl_key"},
+	{&TermInfoEmitEndShiftKey, "chafa_term_info_emit_end_shift_key"},
+	{&TermInfoEmitUpKey, "chafa_term_info_emit_up_key"},
+	{&TermInfoEmitUpCtrlKey, "chafa_term_info_emit_up_ctrl_key"},
+	{&TermInfoEmitUpShiftKey, "chafa_term_info_emit_up_shift_key"},
+	{&TermInfoEmitDownKey, "chafa_term_info_emit_down_key"},
+	{&TermInfoEmitDownCtrlKey, "chafa_term_info_emit_down_ctrl_key"},
+	{&TermInfoEmitDownShiftKey, "chafa_term_info_emit_down_shift_key"},
+	{&TermInfoEmitLeftKey, "chafa_term_info_emit_left_key"},
+	{&TermInfoEmitLeftCtrlKey, "chafa_term_info_emit_left_ctrl_key"},
+	{&TermInfoEmitLeftShiftKey, "chafa_term_info_emit_left_shift_key"},
+	{&TermInfoEmitRightKey, "chafa_term_info_emit_right_key"},
+	{&TermInfoEmitRightCtrlKey, "chafa_term_info_emit_right_ctrl_key"},
+	{&TermInfoEmitRightShiftKey, "chafa_term_info_emit_right_shift_key"},
+	{&TermInfoEmitPageUpKey, "chafa_term_info_emit_page_up_key"},
+	{&TermInfoEmitPageUpCtrlKey, "chafa_term_info_emit_page_up_ctrl_key"},
+	{&TermInfoEmitPageUpShiftKey, "chafa_term_info_emit_page_up_shift_key"},
+	{&TermInfoEmitPageDownKey, "chafa_term_info_emit_page_down_key"},
+	{&TermInfoEmitPageDownCtrlKey, "chafa_term_info_emit_page_down_ctrl_key"},
+	{&TermInfoEmitPageDownShiftKey, "chafa_term_info_emit_page_down_shift_key"},
+	{&TermInfoEmitTabKey, "chafa_term_info_emit_tab_key"},
+	{&TermInfoEmitTabShiftKey, "chafa_term_info_emit_tab_shift_key"},
+	{&TermInfoEmitF1Key, "chafa_term_info_emit_f1_key"},
+	{&TermInfoEmitF1CtrlKey, "chafa_term_info_emit_f1_ctrl_key"},
+	{&TermInfoEmitF1ShiftKey, "chafa_term_info_emit_f1_shift_key"},
+	{&TermInfoEmitF2Key, "chafa_term_info_emit_f2_key"},
+	{&TermInfoEmitF2CtrlKey, "chafa_term_info_emit_f2_ctrl_key"},
+	{&TermInfoEmitF2ShiftKey, "chafa_term_info_emit_f2_shift_key"},
+	{&TermInfoEmitF3Key, "chafa_term_info_emit_f3_key"},
+	{&TermInfoEmitF3CtrlKey, "chafa_term_info_emit_f3_ctrl_key"},
+	{&TermInfoEmitF3ShiftKey, "chafa_term_info_emit_f3_shift_key"},
+	{&TermInfoEmitF4Key, "chafa_term_info_emit_f4_key"},
+	{&TermInfoEmitF4CtrlKey, "chafa_term_info_emit_f4_ctrl_key"},
+	{&TermInfoEmitF4ShiftKey, "chafa_term_info_emit_f4_shift_key"},
+	{&TermInfoEmitF5Key, "chafa_term_info_emit_f5_key"},
+	{&TermInfoEmitF5CtrlKey, "chafa_term_info_emit_f5_ctrl_key"},
+	{&TermInfoEmitF5ShiftKey, "chafa_term_info_emit_f5_shift_key"},
+	{&TermInfoEmitF6Key, "chafa_term_info_emit_f6_key"},
+	{&TermInfoEmitF6CtrlKey, "chafa_term_info_emit_f6_ctrl_key"},
+	{&TermInfoEmitF6ShiftKey, "chafa_term_info_emit_f6_shift_key"},
+	{&TermInfoEmitF7Key, "chafa_term_info_emit_f7_key"},
+	{&TermInfoEmitF7CtrlKey, "chafa_term_info_emit_f7_ctrl_key"},
+	{&TermInfoEmitF7ShiftKey, "chafa_term_info_emit_f7_shift_key"},
+	{&TermInfoEmitF8Key, "chafa_term_info_emit_f8_key"},
+	{&TermInfoEmitF8CtrlKey, "chafa_term_info_emit_f8_ctrl_key"},
+	{&TermInfoEmitF8ShiftKey, "chafa_term_info_emit_f8_shift_key"},
+	{&TermInfoEmitF9Key, "chafa_term_info_emit_f9_key"},
+	{&TermInfoEmitF9CtrlKey, "chafa_term_info_emit_f9_ctrl_key"},
+	{&TermInfoEmitF9ShiftKey, "chafa_term_info_emit_f9_shift_key"},
+	{&TermInfoEmitF10Key, "chafa_term_info_emit_f10_key"},
+	{&TermInfoEmitF10CtrlKey, "chafa_term_info_emit_f10_ctrl_key"},
+	{&TermInfoEmitF10ShiftKey, "chafa_term_info_emit_f10_shift_key"},
+	{&TermInfoEmitF11Key, "chafa_term_info_emit_f11_key"},
+	{&TermInfoEmitF11CtrlKey, "chafa_term_info_emit_f11_ctrl_key"},
+	{&TermInfoEmitF11ShiftKey, "chafa_term_info_emit_f11_shift_key"},
+	{&TermInfoEmitF12Key, "chafa_term_info_emit_f12_key"},
+	{&TermInfoEmitF12CtrlKey, "chafa_term_info_emit_f12_ctrl_key"},
+	{&TermInfoEmitF12ShiftKey, "chafa_term_info_emit_f12_shift_key"},
+
+	// Features
+	{&GetBuiltinFeatures, "chafa_get_builtin_features"},
+	{&GetSupportedFeatures, "chafa_get_supported_features"},
+	{&DescribeFeatures, "chafa_describe_features"},
+	{&GetNThreads, "chafa_get_n_threads"},
+	{&SetNThreads, "chafa_set_n_threads"},
+	{&GetNActualThreads, "chafa_get_n_actual_threads"},
+
+	// Miscellaneous
+	{&CalcCanvasGeometry, "chafa_calc_canvas_geometry"},
+	{&FreeGStringArray, "chafa_free_gstring_array"},
 }
 
+// GString mirrors the leading fields of GLib's GString, a character pointer
+// and a length, which have the same layout as a Go string.
 type GString struct {
 	str string
 }
 
+// Returns the contents of gstr. The string points into C memory, so it is only
+// valid until gstr is freed; use [CanvasPrintString] to get a copy instead.
 func (gstr *GString) String() string {
 	return gstr.str
 }
 
+// GError mirrors GLib's GError. Use [Error] to inspect errors returned by the
+// package's wrappers instead.
 type GError struct {
+	Domain  uint32
+	Code    int32
+	Message *byte
+}
+
+// Error is an error reported by libchafa. Domain and Code identify the kind of
+// error within the GLib error domain, e.g. [TermInfoErrorQuark] with a
+// [TermInfoError] code.
+//
+// Errors in the TermInfo domain can be matched with errors.Is, as in
+// errors.Is(err, CHAFA_TERM_INFO_ERROR_BAD_ESCAPE).
+type Error struct {
 	Domain  uint32
 	Code    int32
 	Message string
 }
 
-func Load(path string) (pixels []uint8, width, height int32, err error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, 0, 0, err
+func (e *Error) Error() string {
+	return "chafa: " + e.Message
+}
+
+// Returns the name of the error's domain, e.g. "chafa-term-info-error-quark".
+func (e *Error) DomainName() string {
+	if !glibAvailable["g_quark_to_string"] {
+		return ""
 	}
-	defer file.Close()
+	return gQuarkToString(e.Domain)
+}
 
-	var img image.Image
+func (e *Error) Is(target error) bool {
+	switch target := target.(type) {
+	case *Error:
+		return e.Domain == target.Domain && e.Code == target.Code
+	case TermInfoError:
+		return HasFunction("chafa_term_info_error_quark") &&
+			e.Domain == TermInfoErrorQuark() && e.Code == int32(target)
+	}
+	return false
+}
 
-	switch filepath.Ext(path) {
-	case "png":
-		img, err = png.Decode(file)
-		if err != nil {
-			return nil, 0, 0, err
-		}
-	case "jpg", "jpeg":
-		img, err = jpeg.Decode(file)
-		if err != nil {
-			return nil, 0, 0, err
-		}
-	case "gif":
-		img, err = gif.Decode(file)
-		if err != nil {
-			return nil, 0, 0, err
-		}
-	default:
-		img, _, err = image.Decode(file)
-		if err != nil {
-			return nil, 0, 0, err
-		}
+// Load decodes the image in the file at path with [LoadFile], and returns its
+// pixels as [CHAFA_PIXEL_RGBA8_UNASSOCIATED] along with its size.
+func Load(path string) (pixels []uint8, width, height int32, err error) {
+	img, err := LoadFile(path)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
 	bounds := img.Bounds()
 	width = int32(bounds.Dx())
 	height = int32(bounds.Dy())
 
-	rgbaImg := image.NewRGBA(bounds)
-	draw.Draw(rgbaImg, bounds, img, bounds.Min, draw.Src)
+	nrgbaImg := image.NewNRGBA(bounds)
+	draw.Draw(nrgbaImg, bounds, img, bounds.Min, draw.Src)
 
-	return rgbaImg.Pix, width, height, nil
+	return nrgbaImg.Pix, width, height, nil
 }
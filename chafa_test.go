@@ -0,0 +1,57 @@
+package chafa
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// requireLibrary skips the test if libchafa cannot be loaded.
+func requireLibrary(t testing.TB) {
+	t.Helper()
+
+	if err := Init(); err != nil {
+		t.Skipf("libchafa not available: %v", err)
+	}
+}
+
+// testImage returns a width x height gradient, shifted by phase so that
+// successive frames differ.
+func testImage(width, height, phase int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(x*255/width + phase),
+				G: uint8(y*255/height + phase),
+				B: uint8((x + y + phase) * 7),
+				A: 0xff,
+			})
+		}
+	}
+	return img
+}
+
+func TestBindingWithoutLibrary(t *testing.T) {
+	if os.Getenv("CHAFA_GO_TEST_NO_LIBRARY") != "" {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrLibraryNotLoaded) || !errors.Is(Init(), ErrLibraryNotLoaded) {
+				t.Errorf("binding panicked with %v, want ErrLibraryNotLoaded", err)
+			}
+		}()
+		CanvasConfigNew()
+		t.Error("binding returned without the library")
+		return
+	}
+
+	// Loading can only fail once per process, so do it in a child
+	cmd := exec.Command(os.Args[0], "-test.run=^TestBindingWithoutLibrary$")
+	cmd.Env = append(os.Environ(), "CHAFA_GO_TEST_NO_LIBRARY=1", LibraryEnv+"=/nonexistent/libchafa.so")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+}
@@ -193,8 +193,7 @@ type CanvasConfig struct {
 	WorkFactor                          float32
 	SymbolMap                           SymbolMap
 	FillSymbolMap                       SymbolMap
-	PreprocessingEnabled                bool
-	FgOnlyEnabled                       bool
+	Flags                               uint32 // C bit fields: preprocessing_enabled is bit 0, fg_only_enabled is bit 1
 	Optimizations                       Optimizations
 	Passthrough                         Passthrough
 }
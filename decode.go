@@ -0,0 +1,232 @@
+package chafa
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"sync"
+)
+
+// DecodeFunc decodes an image from r.
+type DecodeFunc func(r io.Reader) (image.Image, error)
+
+type decoder struct {
+	name   string
+	magic  string
+	decode DecodeFunc
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   []decoder
+)
+
+func init() {
+	RegisterDecoder("png", "\x89PNG\r\n\x1a\n", png.Decode)
+	RegisterDecoder("jpeg", "\xff\xd8", decodeJPEG)
+	RegisterDecoder("gif", "GIF8?a", gif.Decode)
+}
+
+// RegisterDecoder registers a decoder used by [Decode] for images that start
+// with magic, in which "?" matches any byte. Decoders registered later take
+// precedence, so a built-in one can be replaced.
+//
+// This makes it possible to support more formats, e.g. WebP, BMP and TIFF
+// from golang.org/x/image:
+//
+//	chafa.RegisterDecoder("webp", "RIFF????WEBPVP8", webp.Decode)
+//
+// Formats registered with the standard image package are also decoded, so
+// importing it for its side effects works too.
+func RegisterDecoder(name, magic string, decode DecodeFunc) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders = append(decoders, decoder{name, magic, decode})
+}
+
+// Decode decodes an image from r, detecting its format from its first bytes.
+// The orientation of JPEG images is corrected according to their EXIF data.
+func Decode(r io.Reader) (image.Image, error) {
+	br := bufio.NewReader(r)
+
+	if d, ok := sniffDecoder(br); ok {
+		return d.decode(br)
+	}
+
+	img, _, err := image.Decode(br)
+	return img, err
+}
+
+// DecodeBytes is like [Decode], for an image held in memory.
+func DecodeBytes(data []byte) (image.Image, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// LoadFile decodes the image in the file at path. See [Decode].
+func LoadFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
+
+// sniffDecoder returns the most recently registered decoder whose magic
+// matches the start of br.
+func sniffDecoder(br *bufio.Reader) (decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	for i := len(decoders) - 1; i >= 0; i-- {
+		d := decoders[i]
+		b, err := br.Peek(len(d.magic))
+		if err == nil && matchMagic(d.magic, b) {
+			return d, true
+		}
+	}
+
+	return decoder{}, false
+}
+
+func matchMagic(magic string, b []byte) bool {
+	for i, c := range b {
+		if magic[i] != c && magic[i] != '?' {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeJPEG decodes a JPEG image and applies its EXIF orientation.
+func decodeJPEG(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return orientImage(img, jpegOrientation(data)), nil
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG image, from 1 to 8,
+// or 1 if it has none.
+func jpegOrientation(data []byte) int {
+	// Skip SOI, then walk the segments up to the image data
+	data = data[min(len(data), 2):]
+	for len(data) >= 4 && data[0] == 0xff {
+		marker := data[1]
+		length := int(binary.BigEndian.Uint16(data[2:]))
+		if marker == 0xda || length < 2 || length+2 > len(data) {
+			break
+		}
+
+		segment := data[4 : length+2]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		data = data[length+2:]
+	}
+
+	return 1
+}
+
+// exifOrientation finds the orientation tag in IFD0 of TIFF-structured EXIF data.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	// Check the offset before converting it, as it may not fit in an int
+	offset := uint64(order.Uint32(tiff[4:]))
+	if offset+2 > uint64(len(tiff)) {
+		return 1
+	}
+	ifd := int(offset)
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		// Orientation, a SHORT
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			break
+		}
+	}
+
+	return 1
+}
+
+// orientImage transforms img so that it is displayed upright, given its EXIF
+// orientation.
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Rect, img, bounds.Min, draw.Src)
+
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // Rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // Mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // Transposed
+				dx, dy = y, x
+			case 6: // Needs a 90° clockwise rotation
+				dx, dy = h-1-y, x
+			case 7: // Transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // Needs a 90° counterclockwise rotation
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,68 @@
+package chafa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+// exifData builds little-endian TIFF-structured EXIF data with IFD0 at
+// ifdOffset, holding one orientation entry.
+func exifData(ifdOffset uint32, orientation uint16) []byte {
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, ifdOffset)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x0112) // Orientation
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)      // SHORT
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0)
+	return tiff
+}
+
+func TestExifOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		tiff []byte
+		want int
+	}{
+		{"rotated", exifData(8, 6), 6},
+		{"out of range value", exifData(8, 9), 1},
+		{"offset past the end", exifData(1000, 6), 1},
+		{"offset not fitting in an int32", exifData(0xfffffff0, 6), 1},
+		{"truncated", exifData(8, 6)[:12], 1},
+		{"not TIFF", []byte("XX*\x00\x08\x00\x00\x00"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.tiff); got != tt.want {
+				t.Errorf("got orientation %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJPEGOrientation(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, testImage(40, 20, 0), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Insert an APP1 segment with orientation 6 after SOI
+	exif := append([]byte("Exif\x00\x00"), exifData(8, 6)...)
+	segment := []byte{0xff, 0xe1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(exif)+2))
+	segment = append(segment, exif...)
+	data := append(append([]byte{0xff, 0xd8}, segment...), encoded.Bytes()[2:]...)
+
+	img, err := DecodeBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds(); got != image.Rect(0, 0, 20, 40) {
+		t.Errorf("got bounds %v, want the image turned upright to 20x40", got)
+	}
+}
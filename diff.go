@@ -0,0 +1,289 @@
+package chafa
+
+import "errors"
+
+// Unchanged cells between two changed runs on a row are redrawn rather than
+// skipped with a cursor move when there are at most this many of them, since
+// the move would take more bytes.
+const diffMaxGap = 4
+
+// diffCell is the contents of one canvas cell.
+type diffCell struct {
+	char   rune
+	fg, bg int32
+}
+
+// DiffRenderer prints successive states of a symbols mode [Canvas] by only
+// emitting the cells that changed since the previous state, instead of the
+// whole canvas as [CanvasPrint] does. Each run of changed cells is preceded by
+// a [CHAFA_TERM_SEQ_CURSOR_TO_POS] sequence, which makes a big difference for
+// mostly static content such as dashboards, especially over slow links.
+//
+// The canvas is drawn at a fixed position on the screen, see
+// [DiffRenderer.SetOrigin]. The first render, and any render after the
+// canvas' geometry or mode changed, draws every cell.
+//
+// The [CHAFA_CANVAS_MODE_FGBG_BGFG] canvas mode is not supported. A
+// DiffRenderer must not be used by multiple goroutines at once, and must be
+// closed with [DiffRenderer.Close] when no longer needed.
+type DiffRenderer struct {
+	termInfo *TermInfo
+
+	// Screen position of the top left cell, zero-based.
+	col, row int32
+
+	// State of the canvas as last printed. cells is nil if nothing was.
+	width, height int32
+	mode          CanvasMode
+	cells         []diffCell
+}
+
+// NewDiffRenderer creates a [DiffRenderer] that emits sequences from
+// termInfo, which must be able to move the cursor. If termInfo is nil, the
+// fallback [TermInfo] is used.
+func NewDiffRenderer(termInfo *TermInfo) (*DiffRenderer, error) {
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	if termInfo != nil {
+		if !TermInfoHaveSeq(termInfo, CHAFA_TERM_SEQ_CURSOR_TO_POS) {
+			return nil, errors.New("chafa: DiffRenderer needs a terminal that can move the cursor")
+		}
+		TermInfoRef(termInfo)
+	} else {
+		termInfo = TermDbGetFallbackInfo(TermDbGetDefault())
+	}
+
+	return &DiffRenderer{termInfo: termInfo}, nil
+}
+
+// Close releases the [TermInfo] held by d. d must not be used afterwards.
+func (d *DiffRenderer) Close() error {
+	if d.termInfo != nil {
+		TermInfoUnref(d.termInfo)
+		d.termInfo = nil
+	}
+
+	return nil
+}
+
+// SetOrigin sets the screen position, in zero-based character cells, at which
+// the top left corner of the canvas is drawn. The default is the top left
+// corner of the screen. The next render draws every cell.
+func (d *DiffRenderer) SetOrigin(col, row int32) {
+	d.col, d.row = col, row
+	d.Invalidate()
+}
+
+// Invalidate forgets what is on the screen, so the next render draws every
+// cell. Use it after the screen was cleared or drawn over.
+func (d *DiffRenderer) Invalidate() {
+	d.cells = nil
+}
+
+// Render returns the sequences that update the screen from the previously
+// rendered state to the current contents of canvas. Afterwards the cursor is
+// left at the start of the line below the canvas, with attributes reset.
+//
+// An empty string is returned if nothing changed.
+func (d *DiffRenderer) Render(canvas *Canvas) (string, error) {
+	if err := checkFunction("chafa_canvas_get_raw_colors_at"); err != nil {
+		return "", err
+	}
+
+	config := CanvasPeekConfig(canvas)
+	if CanvasConfigGetPixelMode(config) != CHAFA_PIXEL_MODE_SYMBOLS {
+		return "", errors.New("chafa: DiffRenderer needs a canvas in symbols mode")
+	}
+
+	mode := CanvasConfigGetCanvasMode(config)
+	if mode == CHAFA_CANVAS_MODE_FGBG_BGFG {
+		return "", errors.New("chafa: DiffRenderer does not support the FGBG_BGFG canvas mode")
+	}
+
+	var width, height int32
+	CanvasConfigGetGeometry(config, &width, &height)
+
+	cells := make([]diffCell, int(width)*int(height))
+	for y := int32(0); y < height; y++ {
+		for x := int32(0); x < width; x++ {
+			cell := &cells[y*width+x]
+			cell.char = CanvasGetCharAt(canvas, x, y)
+			CanvasGetRawColorsAt(canvas, x, y, &cell.fg, &cell.bg)
+		}
+	}
+
+	prev := d.cells
+	if width != d.width || height != d.height || mode != d.mode {
+		prev = nil
+	}
+
+	e := diffEmitter{termInfo: d.termInfo, mode: mode}
+	for y := int32(0); y < height; y++ {
+		row := cells[y*width:][:width]
+		var prevRow []diffCell
+		if prev != nil {
+			prevRow = prev[y*width:][:width]
+		}
+
+		e.row(row, prevRow, d.col, d.row+y)
+	}
+
+	d.cells, d.width, d.height, d.mode = cells, width, height, mode
+
+	if len(e.buf) == 0 {
+		return "", nil
+	}
+
+	e.finish(d.col, d.row+height)
+
+	return string(e.buf), nil
+}
+
+// diffEmitter builds the output of [DiffRenderer.Render], keeping track of
+// the colors currently set on the terminal.
+type diffEmitter struct {
+	termInfo *TermInfo
+	mode     CanvasMode
+	buf      []byte
+
+	// Current colors, -1 being the terminal default. Unknown until colorsSet.
+	fg, bg    int32
+	colorsSet bool
+}
+
+// row emits the cells of row that differ from prevRow, at screen row y. A
+// nil prevRow means every cell differs.
+func (e *diffEmitter) row(row, prevRow []diffCell, col, y int32) {
+	changed := func(x int) bool {
+		return prevRow == nil || row[x] != prevRow[x]
+	}
+
+	for x := 0; x < len(row); {
+		if !changed(x) {
+			x++
+			continue
+		}
+
+		// Find the end of the run, bridging short gaps of unchanged cells
+		end, gap := x+1, 0
+		for end < len(row) {
+			if changed(end) {
+				gap = 0
+			} else if gap++; gap > diffMaxGap {
+				break
+			}
+			end++
+		}
+		end -= min(gap, diffMaxGap)
+
+		// Start on the left half of a double-width character
+		start := x
+		if start > 0 && row[start].char == 0 {
+			start--
+		}
+
+		e.run(row[start:end], col+int32(start), y)
+		x = end
+	}
+}
+
+// run emits cells, starting at screen position (x, y).
+func (e *diffEmitter) run(cells []diffCell, x, y int32) {
+	e.cursorToPos(x, y)
+
+	for _, cell := range cells {
+		// The right half of a double-width character
+		if cell.char == 0 {
+			continue
+		}
+
+		e.colors(cell.fg, cell.bg)
+		e.buf = append(e.buf, string(cell.char)...)
+	}
+}
+
+// colors makes fg and bg the current colors.
+func (e *diffEmitter) colors(fg, bg int32) {
+	if e.mode == CHAFA_CANVAS_MODE_FGBG {
+		return
+	}
+	if e.colorsSet && fg == e.fg && bg == e.bg {
+		return
+	}
+
+	// Only a reset goes back to the default colors
+	if !e.colorsSet || (fg < 0 && e.fg >= 0) || (bg < 0 && e.bg >= 0) {
+		e.resetAttributes()
+		e.fg, e.bg, e.colorsSet = -1, -1, true
+	}
+
+	setFg := fg >= 0 && fg != e.fg
+	setBg := bg >= 0 && bg != e.bg
+
+	ti := e.termInfo
+	switch e.mode {
+	case CHAFA_CANVAS_MODE_TRUECOLOR:
+		fgR, fgG, fgB := unpackRGB(fg)
+		bgR, bgG, bgB := unpackRGB(bg)
+		switch {
+		case setFg && setBg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorFgbgDirect(ti, dest, fgR, fgG, fgB, bgR, bgG, bgB) })
+		case setFg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorFgDirect(ti, dest, fgR, fgG, fgB) })
+		case setBg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorBgDirect(ti, dest, bgR, bgG, bgB) })
+		}
+
+	case CHAFA_CANVAS_MODE_INDEXED_256, CHAFA_CANVAS_MODE_INDEXED_240:
+		switch {
+		case setFg && setBg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorFgbg256(ti, dest, uint8(fg), uint8(bg)) })
+		case setFg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorFg256(ti, dest, uint8(fg)) })
+		case setBg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorBg256(ti, dest, uint8(bg)) })
+		}
+
+	default:
+		// The 16 color sequences also cover the 8 color modes, whose pens are a subset
+		switch {
+		case setFg && setBg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorFgbg16(ti, dest, uint8(fg), uint8(bg)) })
+		case setFg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorFg16(ti, dest, uint8(fg)) })
+		case setBg:
+			e.emit(func(dest *byte) *byte { return TermInfoEmitSetColorBg16(ti, dest, uint8(bg)) })
+		}
+	}
+
+	e.fg, e.bg = fg, bg
+}
+
+// finish resets the attributes and moves the cursor to screen position (x, y).
+func (e *diffEmitter) finish(x, y int32) {
+	if e.colorsSet && (e.fg >= 0 || e.bg >= 0) {
+		e.resetAttributes()
+	}
+
+	e.cursorToPos(x, y)
+}
+
+// cursorToPos moves the cursor to the zero-based screen position (x, y).
+func (e *diffEmitter) cursorToPos(x, y int32) {
+	e.emit(func(dest *byte) *byte { return TermInfoEmitCursorToPos(e.termInfo, dest, uint32(x), uint32(y)) })
+}
+
+func (e *diffEmitter) resetAttributes() {
+	e.emit(func(dest *byte) *byte { return TermInfoEmitResetAttributes(e.termInfo, dest) })
+}
+
+func (e *diffEmitter) emit(f func(dest *byte) *byte) {
+	e.buf = append(e.buf, TermInfoEmitString(f)...)
+}
+
+// unpackRGB splits a packed 0x00RRGGBB color into its components.
+func unpackRGB(c int32) (r, g, b uint8) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
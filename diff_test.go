@@ -0,0 +1,55 @@
+package chafa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffRenderer(t *testing.T) {
+	requireLibrary(t)
+
+	config := CanvasConfigNew()
+	defer CanvasConfigUnref(config)
+	CanvasConfigSetGeometry(config, 4, 2)
+	CanvasConfigSetCanvasMode(config, CHAFA_CANVAS_MODE_TRUECOLOR)
+
+	canvas := CanvasNew(config)
+	defer CanvasUnRef(canvas)
+	for y := int32(0); y < 2; y++ {
+		for x := int32(0); x < 4; x++ {
+			CanvasSetCharAt(canvas, x, y, 'a')
+			CanvasSetColorsAt(canvas, x, y, 0x102030, 0x405060)
+		}
+	}
+
+	termInfo := TermDbDetect(TermDbGetDefault(), []string{"TERM=xterm-256color", "COLORTERM=truecolor"})
+	diff, err := NewDiffRenderer(termInfo)
+	TermInfoUnref(termInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diff.Close()
+	diff.SetOrigin(2, 1)
+
+	out, err := diff.Render(canvas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "\x1b[2;3H") || strings.Count(out, "a") != 8 || !strings.Contains(out, "\x1b[38;2;16;32;48") {
+		t.Errorf("first render = %q", out)
+	}
+
+	CanvasSetCharAt(canvas, 3, 1, 'b')
+	out, err = diff.Render(canvas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "\x1b[3;6H") || strings.Contains(out, "a") || !strings.Contains(out, "b") ||
+		!strings.HasSuffix(out, "\x1b[4;3H") {
+		t.Errorf("render of one changed cell = %q", out)
+	}
+
+	if out, err := diff.Render(canvas); out != "" || err != nil {
+		t.Errorf("render without changes = %q, %v", out, err)
+	}
+}
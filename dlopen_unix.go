@@ -0,0 +1,16 @@
+//go:build darwin || freebsd || linux
+
+package chafa
+
+import "github.com/ebitengine/purego"
+
+// openLibrary loads the shared library at path, which may also be a bare
+// file name to be resolved by the dynamic loader.
+func openLibrary(path string) (uintptr, error) {
+	return purego.Dlopen(path, purego.RTLD_NOW|purego.RTLD_GLOBAL)
+}
+
+// lookupSymbol returns the address of the symbol name in the library lib.
+func lookupSymbol(lib uintptr, name string) (uintptr, error) {
+	return purego.Dlsym(lib, name)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package chafa
+
+import "syscall"
+
+// openLibrary loads the DLL at path, which may also be a bare file name to be
+// resolved using the standard DLL search order.
+func openLibrary(path string) (uintptr, error) {
+	handle, err := syscall.LoadLibrary(path)
+	return uintptr(handle), err
+}
+
+// lookupSymbol returns the address of the function name exported by the DLL lib.
+func lookupSymbol(lib uintptr, name string) (uintptr, error) {
+	return syscall.GetProcAddress(syscall.Handle(lib), name)
+}
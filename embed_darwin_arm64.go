@@ -0,0 +1,14 @@
+// Code generated by internal/cmd/genmanifest from libs/CHECKSUMS.txt; DO NOT EDIT.
+
+//go:build darwin && arm64 && !chafa_noembed
+
+package chafa
+
+import "embed"
+
+// embeddedLibs holds the library for darwin/arm64 only.
+//
+//go:embed libs/darwin-arm64/libchafa.dylib
+var embeddedLibs embed.FS
+
+const embeddingDisabled = false
@@ -0,0 +1,14 @@
+// Code generated by internal/cmd/genmanifest from libs/CHECKSUMS.txt; DO NOT EDIT.
+
+//go:build linux && 386 && !chafa_noembed
+
+package chafa
+
+import "embed"
+
+// embeddedLibs holds the library for linux/386 only.
+//
+//go:embed libs/linux-386/libchafa.so
+var embeddedLibs embed.FS
+
+const embeddingDisabled = false
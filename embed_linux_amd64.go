@@ -0,0 +1,14 @@
+// Code generated by internal/cmd/genmanifest from libs/CHECKSUMS.txt; DO NOT EDIT.
+
+//go:build linux && amd64 && !chafa_noembed
+
+package chafa
+
+import "embed"
+
+// embeddedLibs holds the library for linux/amd64 only.
+//
+//go:embed libs/linux-amd64/libchafa.so
+var embeddedLibs embed.FS
+
+const embeddingDisabled = false
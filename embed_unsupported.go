@@ -0,0 +1,12 @@
+// Code generated by internal/cmd/genmanifest from libs/CHECKSUMS.txt; DO NOT EDIT.
+
+//go:build !chafa_noembed && !((darwin && arm64) || (linux && 386) || (linux && amd64) || (windows && amd64))
+
+package chafa
+
+import "embed"
+
+// embeddedLibs is empty, since there is no library for this platform.
+var embeddedLibs embed.FS
+
+const embeddingDisabled = false
@@ -0,0 +1,14 @@
+// Code generated by internal/cmd/genmanifest from libs/CHECKSUMS.txt; DO NOT EDIT.
+
+//go:build windows && amd64 && !chafa_noembed
+
+package chafa
+
+import "embed"
+
+// embeddedLibs holds the library for windows/amd64 only.
+//
+//go:embed libs/windows-amd64/libchafa.dll
+var embeddedLibs embed.FS
+
+const embeddingDisabled = false
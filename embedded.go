@@ -19,20 +19,152 @@
 // The embedded library is extracted to a user-specific temporary directory and
 // loaded dynamically. If extraction fails, the code falls back to the traditional
 // method of searching system paths.
+//
+// Since the temporary directory may be shared with other users, the library is
+// verified against its SHA-256 sum from libs/CHECKSUMS.txt both before and after
+// extraction. Each checksum gets its own directory, so different versions of
+// this package never reuse each other's files, and the directories must be
+// private to the current user. Files are written to a temporary name and
+// renamed into place, so a partially written library is never loaded.
+//
+// On Linux, the library can instead be loaded straight from memory using
+// memfd_create(2), which keeps working when /tmp is read-only or mounted noexec.
+// See [EmbeddedStrategy].
 package chafa
 
 import (
-	"embed"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
 )
 
-//go:embed libs/*
-var embeddedLibs embed.FS
+//go:generate go run ./internal/cmd/genmanifest
+
+// ErrEmbeddingDisabled is reported for the embedded library source when the
+// package was built with the chafa_noembed build tag.
+var ErrEmbeddingDisabled = errors.New("chafa: embedded library disabled by the chafa_noembed build tag")
+
+// embeddedPlatform describes the library embedded for one platform. The
+// table of them, embeddedPlatforms, is generated from libs/CHECKSUMS.txt
+// along with the embed_*.go files, which use build tags so that each binary
+// only carries the library for its own platform.
+type embeddedPlatform struct {
+	// Path within embeddedLibs, always libs/<GOOS>-<GOARCH>/<file>.
+	path string
+
+	// Hex-encoded SHA-256 sum of the file.
+	sha256 string
+}
+
+// EmbeddedStrategy selects how the embedded library is handed to the dynamic loader.
+type EmbeddedStrategy int32
+
+const (
+	// Load the library from memory where supported, falling back to a
+	// temporary file. This is the default.
+	EmbeddedStrategyAuto EmbeddedStrategy = 0
+
+	// Only load the library from an anonymous in-memory file created with
+	// memfd_create(2). This never touches the filesystem, so it works with a
+	// read-only root and a noexec /tmp, but is only available on Linux.
+	EmbeddedStrategyMemory EmbeddedStrategy = 1
+
+	// Only extract the library to a private directory under os.TempDir.
+	EmbeddedStrategyTempDir EmbeddedStrategy = 2
+)
+
+var embeddedStrategy = EmbeddedStrategyAuto
+
+// SetEmbeddedStrategy selects how the embedded library is loaded. It must
+// be called before the library is loaded.
+func SetEmbeddedStrategy(strategy EmbeddedStrategy) error {
+	libConfigMu.Lock()
+	defer libConfigMu.Unlock()
+
+	if libLoadedOnce {
+		return ErrLibraryAlreadyLoaded
+	}
+	embeddedStrategy = strategy
+
+	return nil
+}
+
+// embeddedCandidates returns the ways of loading the embedded library allowed
+// by the current [EmbeddedStrategy], in order of preference.
+func embeddedCandidates() []libraryCandidate {
+	if embeddingDisabled {
+		return []libraryCandidate{{err: ErrEmbeddingDisabled}}
+	}
+
+	var candidates []libraryCandidate
+
+	// Both are prepared only when tried, so the library is not written to
+	// disk unless loading it from memory failed
+	if embeddedStrategy != EmbeddedStrategyTempDir {
+		candidates = append(candidates, libraryCandidate{prepare: func(c *libraryCandidate) {
+			c.path, c.release, c.err = memfdEmbeddedLibrary()
+		}})
+	}
+	if embeddedStrategy != EmbeddedStrategyMemory {
+		candidates = append(candidates, libraryCandidate{prepare: func(c *libraryCandidate) {
+			c.path, c.err = extractEmbeddedLibrary()
+		}})
+	}
+
+	return candidates
+}
+
+var (
+	embeddedOnce sync.Once
+	embeddedName string
+	embeddedData []byte
+	embeddedSum  string
+	embeddedErr  error
+)
+
+// embeddedLibrary returns the file name, contents and hex-encoded SHA-256
+// sum of the library for the current platform, after checking the contents
+// against the sum.
+func embeddedLibrary() (name string, data []byte, sum string, err error) {
+	embeddedOnce.Do(func() {
+		platform := runtime.GOOS + "/" + runtime.GOARCH
+
+		lib, ok := embeddedPlatforms[platform]
+		if !ok {
+			embeddedErr = fmt.Errorf(
+				"no embedded library for %s (available: %s)",
+				platform,
+				strings.Join(embeddedPlatformNames(), ", "),
+			)
+			return
+		}
+
+		// Read the embedded library and make sure it matches its checksum
+		// before anything is done with it
+		data, err := embeddedLibs.ReadFile(lib.path)
+		if err != nil {
+			embeddedErr = fmt.Errorf("failed to open embedded library %s: %w", lib.path, err)
+			return
+		}
+		if got := sha256.Sum256(data); hex.EncodeToString(got[:]) != lib.sha256 {
+			embeddedErr = fmt.Errorf("embedded library %s does not match its checksum", lib.path)
+			return
+		}
+
+		embeddedName, embeddedData, embeddedSum = path.Base(lib.path), data, lib.sha256
+	})
+
+	return embeddedName, embeddedData, embeddedSum, embeddedErr
+}
 
 var (
 	extractOnce   sync.Once
@@ -44,86 +176,137 @@ var (
 // to a temporary directory and returns the path to the extracted library
 func extractEmbeddedLibrary() (string, error) {
 	extractOnce.Do(func() {
-		// Determine platform-specific details
-		var libName string
-		var platformDir string
-
-		switch runtime.GOOS {
-		case "darwin":
-			libName = "libchafa.dylib"
-		case "linux":
-			libName = "libchafa.so"
-		case "windows":
-			libName = "libchafa.dll"
-		default:
-			extractErr = fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+		libName, data, want, err := embeddedLibrary()
+		if err != nil {
+			extractErr = err
 			return
 		}
 
-		// Determine architecture suffix
-		var archSuffix string
-		switch runtime.GOARCH {
-		case "amd64":
-			archSuffix = "amd64"
-		case "arm64":
-			archSuffix = "arm64"
-		case "386":
-			archSuffix = "386"
-		default:
-			extractErr = fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+		// Create a private directory for the current user, and one inside it
+		// for this exact build of the library
+		baseDir := filepath.Join(os.TempDir(), fmt.Sprintf("chafa-go-%d", os.Getuid()))
+		if err := makePrivateDir(baseDir); err != nil {
+			extractErr = err
 			return
 		}
 
-		// Create platform directory string
-		platformDir = fmt.Sprintf("%s-%s", runtime.GOOS, archSuffix)
-
-		// Create a unique temporary directory for the current user
-		tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("chafa-go-%d", os.Getuid()))
-		if err := os.MkdirAll(tempDir, 0755); err != nil {
-			extractErr = fmt.Errorf("failed to create temp directory: %w", err)
+		libDir := filepath.Join(baseDir, want[:16])
+		if err := makePrivateDir(libDir); err != nil {
+			extractErr = err
 			return
 		}
 
-		// Path to the library within the embedded filesystem
-		libPath := filepath.Join("libs", platformDir, libName)
-
 		// Where the library will be extracted
-		extractedPath = filepath.Join(tempDir, libName)
+		path := filepath.Join(libDir, libName)
 
-		// Check if library already exists and is valid
-		if stat, err := os.Stat(extractedPath); err == nil && stat.Size() > 0 {
-			// Library already exists, nothing to do
+		// Reuse an earlier extraction only if it is intact
+		if err := verifyFile(path, want); err == nil {
+			extractedPath = path
 			return
 		}
 
-		// Open the embedded library
-		embeddedLib, err := embeddedLibs.Open(libPath)
-		if err != nil {
-			extractErr = fmt.Errorf("failed to open embedded library %s: %w", libPath, err)
-			return
-		}
-		defer embeddedLib.Close()
-
-		// Create the output file
-		outFile, err := os.Create(extractedPath)
-		if err != nil {
-			extractErr = fmt.Errorf("failed to create output file: %w", err)
-			return
-		}
-		defer outFile.Close()
-
-		// Copy the library to the temporary directory
-		if _, err := io.Copy(outFile, embeddedLib); err != nil {
+		if err := writeFileAtomic(path, data, want); err != nil {
 			extractErr = fmt.Errorf("failed to extract library: %w", err)
 			return
 		}
 
-		// On Unix systems, make the library executable
-		if err := os.Chmod(extractedPath, 0755); err != nil {
-			extractErr = fmt.Errorf("failed to make library executable: %w", err)
-			return
-		}
+		extractedPath = path
 	})
 
 	return extractedPath, extractErr
 }
+
+// embeddedPlatformNames returns the sorted GOOS/GOARCH pairs that have an
+// embedded library.
+func embeddedPlatformNames() []string {
+	names := make([]string, 0, len(embeddedPlatforms))
+	for name := range embeddedPlatforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// makePrivateDir creates dir with 0700 permissions if it doesn't exist, and
+// otherwise makes sure it is a real directory owned by the current user that
+// no one else can write to. On Windows only the first two hold, as
+// checkPrivate does nothing there.
+func makePrivateDir(dir string) error {
+	if err := os.Mkdir(dir, 0o700); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if err := checkPrivate(info); err != nil {
+		return fmt.Errorf("refusing to use %s: %w", dir, err)
+	}
+
+	return nil
+}
+
+// verifyFile checks that the regular file at path has the hex-encoded SHA-256 sum want.
+func verifyFile(path, want string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != want {
+		return fmt.Errorf("%s does not match its checksum", path)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path, verifies it
+// and renames it into place.
+func writeFileAtomic(path string, data []byte, want string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// On Unix systems, make the library executable
+	if err := os.Chmod(tmp.Name(), 0o700); err != nil {
+		return err
+	}
+
+	if err := verifyFile(tmp.Name(), want); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
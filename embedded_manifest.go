@@ -0,0 +1,14 @@
+// Code generated by internal/cmd/genmanifest from libs/CHECKSUMS.txt; DO NOT EDIT.
+
+package chafa
+
+// embeddedChafaVersion is the Chafa release the embedded libraries were built from.
+const embeddedChafaVersion = "1.16"
+
+// embeddedPlatforms maps GOOS/GOARCH to the library embedded for that platform.
+var embeddedPlatforms = map[string]embeddedPlatform{
+	"darwin/arm64":  {path: "libs/darwin-arm64/libchafa.dylib", sha256: "10ee99be32cbeebbd9ed893653381f168b668fa508fde8a36c8e17f4caa00b9e"},
+	"linux/386":     {path: "libs/linux-386/libchafa.so", sha256: "7504b67767077ba04b98291084dc2583c8a5c07b740c370a7ce3b5d8ce8cfeee"},
+	"linux/amd64":   {path: "libs/linux-amd64/libchafa.so", sha256: "5f7df37965dd03c0269dcefec685863945123f9dee5bd3c6c4014709e3bf054a"},
+	"windows/amd64": {path: "libs/windows-amd64/libchafa.dll", sha256: "d78d479ece127168fab833b6010ad3ebcab3c5f900abb4ad175b9edbe04049fd"},
+}
@@ -0,0 +1,89 @@
+package chafa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestEmbeddedMemoryLoadSkipsExtraction(t *testing.T) {
+	if runtime.GOOS != "linux" || embeddingDisabled {
+		t.Skip("loading from memory needs Linux and the embedded library")
+	}
+
+	if os.Getenv("CHAFA_GO_TEST_MEMFD") != "" {
+		if err := Init(); err != nil {
+			t.Fatal(err)
+		}
+		if source := GetLibraryReport().Source; source != LibrarySourceEmbedded {
+			t.Fatalf("loaded from source %v, want the embedded library", source)
+		}
+		return
+	}
+
+	// The library may already be loaded in this process, so load it in a child
+	tmp := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestEmbeddedMemoryLoadSkipsExtraction$")
+	cmd.Env = append(os.Environ(), "CHAFA_GO_TEST_MEMFD=1", "TMPDIR="+tmp, LibraryEnv+"=")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("loading from memory wrote %s to the temporary directory", entries[0].Name())
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("not really a library")
+	sum := sha256.Sum256(data)
+	want := hex.EncodeToString(sum[:])
+
+	path := filepath.Join(dir, "libchafa.so")
+	if err := writeFileAtomic(path, data, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyFile(path, want); err != nil {
+		t.Errorf("intact file: %v", err)
+	}
+
+	// Flip one byte, as a tampered or truncated extraction would
+	tampered := bytes.Clone(data)
+	tampered[0] ^= 1
+	if err := os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyFile(path, want); err == nil {
+		t.Error("tampered file passed verification")
+	}
+
+	if err := verifyFile(dir, want); err == nil {
+		t.Error("directory passed verification")
+	}
+	if err := verifyFile(filepath.Join(dir, "missing.so"), want); err == nil {
+		t.Error("missing file passed verification")
+	}
+
+	// writeFileAtomic must not leave anything behind if the data is wrong
+	other := filepath.Join(dir, "other.so")
+	if err := writeFileAtomic(other, tampered, want); err == nil {
+		t.Error("writeFileAtomic accepted data that does not match the checksum")
+	}
+	if _, err := os.Stat(other); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("writeFileAtomic left %s behind: %v", other, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only libchafa.so", len(entries))
+	}
+}
@@ -4,10 +4,7 @@ go 1.24.3
 
 replace github.com/ploMP4/chafa-go => ../..
 
-require (
-	github.com/ploMP4/chafa-go v0.0.0
-	golang.org/x/term v0.32.0
-)
+require github.com/ploMP4/chafa-go v0.0.0
 
 require (
 	github.com/ebitengine/purego v0.8.3 // indirect
@@ -5,7 +5,6 @@ import (
 	"os"
 
 	"github.com/ploMP4/chafa-go"
-	"golang.org/x/sys/unix"
 )
 
 const (
@@ -14,13 +13,6 @@ const (
 	N_CHANNELS = 4
 )
 
-type TermSize struct {
-	widthCells   int32
-	heightCells  int32
-	widthPixels  int32
-	heightPixels int32
-}
-
 func detectTerminal() (*chafa.TermInfo, chafa.CanvasMode, chafa.PixelMode, chafa.Passthrough, *chafa.SymbolMap) {
 	//Examine the environment variables and guess what the terminal can do
 	termInfo := chafa.TermDbDetect(chafa.TermDbGetDefault(), os.Environ())
@@ -40,45 +32,6 @@ func detectTerminal() (*chafa.TermInfo, chafa.CanvasMode, chafa.PixelMode, chafa
 	return termInfo, mode, pixelMode, passthrough, symbolMap
 }
 
-func getTTYSize() TermSize {
-	termSize := TermSize{
-		widthCells:   -1,
-		heightCells:  -1,
-		widthPixels:  -1,
-		heightPixels: -1,
-	}
-
-	fds := []uintptr{os.Stdout.Fd(), os.Stderr.Fd(), os.Stdin.Fd()}
-	for _, fd := range fds {
-		winsize, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
-		if err == nil {
-			termSize.widthCells = int32(winsize.Col)
-			termSize.heightCells = int32(winsize.Row)
-			termSize.widthPixels = int32(winsize.Xpixel)
-			termSize.heightPixels = int32(winsize.Ypixel)
-			break
-		}
-	}
-
-	if termSize.widthCells <= 0 {
-		termSize.widthCells = -1
-	}
-	if termSize.heightCells <= 2 {
-		termSize.heightCells = -1
-	}
-
-	// If .xpixel and .ypixel are filled out, we can calculate
-	// aspect information for the font used. Sixel-capable terminals
-	// like mlterm set these fields, but most others do not.
-
-	if termSize.widthPixels <= 0 || termSize.heightPixels <= 0 {
-		termSize.widthPixels = -1
-		termSize.heightPixels = -1
-	}
-
-	return termSize
-}
-
 func convertImage(
 	pixels []uint8,
 	pixWidth, pixHeight, pixRowstride int32,
@@ -112,9 +65,7 @@ func convertImage(
 	chafa.CanvasDrawAllPixels(canvas, pixelType, pixels, pixWidth, pixHeight, pixRowstride)
 
 	// Build printable string
-	printable := chafa.CanvasPrint(canvas, termInfo)
-
-	return printable.String()
+	return chafa.CanvasPrintString(canvas, termInfo)
 }
 
 func main() {
@@ -127,19 +78,22 @@ func main() {
 	var fontRatio float32 = 0.5
 	var cellWidth int32 = -1
 	var cellHeight int32 = -1
-
-	termSize := getTTYSize()
-
-	if termSize.widthCells > 0 && termSize.heightCells > 0 &&
-		termSize.widthPixels > 0 && termSize.heightPixels > 0 {
-		cellWidth = termSize.widthPixels / termSize.widthCells
-		cellHeight = termSize.heightPixels / termSize.heightCells
-		fontRatio = float32(cellWidth) / float32(cellHeight)
+	var widthCells int32 = -1
+	var heightCells int32 = -1
+
+	geometry, err := chafa.TerminalGeometry(os.Stdout)
+	if err == nil {
+		widthCells = geometry.Width
+		if geometry.Height > 2 {
+			heightCells = geometry.Height
+		}
+		if geometry.CellWidth > 0 && geometry.CellHeight > 0 {
+			cellWidth = geometry.CellWidth
+			cellHeight = geometry.CellHeight
+		}
+		fontRatio = geometry.FontRatio
 	}
 
-	widthCells := termSize.widthCells
-	heightCells := termSize.heightCells
-
 	chafa.CalcCanvasGeometry(
 		PIX_HEIGHT,
 		PIX_HEIGHT,
@@ -49,7 +49,7 @@ func main() {
 		int32(width)*N_CHANNELS,
 	)
 
-	gs := chafa.CanvasPrint(canvas, nil)
+	output := chafa.CanvasPrintString(canvas, nil)
 
-	fmt.Println(gs)
+	fmt.Println(output)
 }
@@ -7,11 +7,7 @@ import (
 	"github.com/ploMP4/chafa-go"
 )
 
-const (
-	FONT_WIDTH  = 18
-	FONT_HEIGHT = 36
-	N_CHANNELS  = 4
-)
+const N_CHANNELS = 4
 
 func main() {
 	if len(os.Args) < 2 {
@@ -38,19 +34,15 @@ func main() {
 	config := chafa.CanvasConfigNew()
 	defer chafa.CanvasConfigUnref(config)
 
-	chafa.CanvasConfigSetGeometry(config, 40, 20)
-	chafa.CanvasConfigSetCellGeometry(config, FONT_WIDTH, FONT_HEIGHT)
 	chafa.CanvasConfigSetCanvasMode(config, mode)
 	chafa.CanvasConfigSetPixelMode(config, pixelMode)
 	chafa.CanvasConfigSetPassthrough(config, passthrough)
 
-	chafa.CalcCanvasGeometry(
-		width, height,
-		&config.Width, &config.Height,
-		float32(FONT_WIDTH)/float32(FONT_HEIGHT),
-		false, false,
-	)
-	chafa.CanvasConfigSetGeometry(config, config.Width, config.Height)
+	geometry, err := chafa.TerminalGeometry(os.Stdout)
+	if err != nil {
+		geometry = chafa.TermGeometry{Width: 40, Height: 21, FontRatio: 0.5}
+	}
+	chafa.FitToTerminal(config, geometry, width, height)
 
 	canvas := chafa.CanvasNew(config)
 	defer chafa.CanvasUnRef(canvas)
@@ -74,7 +66,7 @@ func main() {
 
 	chafa.CanvasSetPlacement(canvas, placement)
 
-	gs := chafa.CanvasPrint(canvas, termInfo)
+	output := chafa.CanvasPrintString(canvas, termInfo)
 
-	fmt.Println(gs)
+	fmt.Println(output)
 }
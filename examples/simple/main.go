@@ -19,6 +19,11 @@ func main() {
 		0xff, 0x00, 0x00, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0xff,
 	}
 
+	if err := chafa.Init(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	// Specify the symbols we want
 	symbolMap := chafa.SymbolMapNew()
 	defer chafa.SymbolMapUnref(symbolMap)
@@ -47,7 +52,7 @@ func main() {
 	)
 
 	// Generate a string that will show the canvas contents on a terminal
-	gs := chafa.CanvasPrint(canvas, nil)
+	output := chafa.CanvasPrintString(canvas, nil)
 
-	fmt.Println(gs)
+	fmt.Println(output)
 }
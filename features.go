@@ -26,6 +26,16 @@ var (
 	GetNActualThreads func() int32
 )
 
+// Queries the number of worker threads like [GetNActualThreads], but returns an
+// error if the loaded libchafa lacks chafa_get_n_actual_threads (it needs 1.8 or newer).
+func GetNActualThreadsGo() (int32, error) {
+	if err := checkFunction("chafa_get_n_actual_threads"); err != nil {
+		return 0, err
+	}
+
+	return GetNActualThreads(), nil
+}
+
 type Features int32
 
 const (
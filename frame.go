@@ -1,5 +1,7 @@
 package chafa
 
+import "image"
+
 var (
 	// Creates a new [Frame] containing a copy of the image data pointed to by data.
 	FrameNew func(data []uint8, pixelType PixelType, width, height, rowstride int32) *Frame
@@ -29,6 +31,23 @@ var (
 	FrameUnref func(frame *Frame)
 )
 
+// Creates a new [Frame] containing a copy of the pixel data of img, using the
+// same zero-conversion rules as [CanvasDrawImage].
+func FrameNewImage(img image.Image) *Frame {
+	pixels, pixelType, width, height, rowstride := imagePixels(img)
+	return FrameNew(pixels, pixelType, width, height, rowstride)
+}
+
+// Creates a new [Frame] like [FrameNew], but returns an error if the loaded
+// libchafa does not support frames (it needs 1.14 or newer).
+func FrameNewGo(data []uint8, pixelType PixelType, width, height, rowstride int32) (*Frame, error) {
+	if err := checkFunction("chafa_frame_new"); err != nil {
+		return nil, err
+	}
+
+	return FrameNew(data, pixelType, width, height, rowstride), nil
+}
+
 type Frame struct {
 	Refs                     int32
 	PixelType                PixelType
@@ -0,0 +1,174 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// FrameStream plays a stream of raw video frames, such as the output of
+// `ffmpeg -f rawvideo -pix_fmt rgb24 -`, on a terminal.
+//
+// Frames are read back to back from a reader, each exactly width*height
+// pixels of the stream's [PixelType] with no padding between rows. They are
+// rendered with a [Renderer], whose canvas is reused since all frames share
+// a size, and drawn over each other like the frames of a [Player]. A
+// FrameStream should be closed with [FrameStream.Close] when no longer needed.
+type FrameStream struct {
+	src       io.Reader
+	width     int32
+	height    int32
+	pixelType PixelType
+	renderer  *Renderer
+	termInfo  *TermInfo
+
+	frame []uint8
+
+	// Updated by Play, and read from other goroutines
+	rendered, dropped atomic.Int64
+}
+
+// NewFrameStream creates a [FrameStream] that reads frames of width x height
+// pixels of type pixelType from src, and renders them with renderer.
+func NewFrameStream(
+	src io.Reader,
+	width, height int32,
+	pixelType PixelType,
+	renderer *Renderer,
+) (*FrameStream, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("chafa: invalid frame size %dx%d", width, height)
+	}
+	if pixelType < 0 || pixelType >= CHAFA_PIXEL_MAX {
+		return nil, fmt.Errorf("chafa: invalid pixel type %d", pixelType)
+	}
+
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	s := &FrameStream{
+		src:       src,
+		width:     width,
+		height:    height,
+		pixelType: pixelType,
+		renderer:  renderer,
+		termInfo:  cursorTermInfo(renderer),
+		frame:     make([]uint8, int(width)*int(height)*pixelTypeBytes(pixelType)),
+	}
+	runtime.SetFinalizer(s, (*FrameStream).free)
+
+	return s, nil
+}
+
+// Close releases the [TermInfo] held by s. It must not be called while Play
+// runs, and s must not be played afterwards. It is safe to call Close more
+// than once.
+func (s *FrameStream) Close() error {
+	s.free()
+	runtime.SetFinalizer(s, nil)
+
+	return nil
+}
+
+func (s *FrameStream) free() {
+	if s.termInfo != nil {
+		TermInfoUnref(s.termInfo)
+		s.termInfo = nil
+	}
+}
+
+// Play reads frames until the end of the stream, writing them to w at fps
+// frames per second. Frames that are already late by the time they have been
+// read, because rendering or w is too slow, are dropped to catch up.
+//
+// Play returns nil at the end of the stream, and io.ErrUnexpectedEOF if it
+// ends partway through a frame. It stops early when ctx is done, but can not
+// interrupt a read from the stream that blocks.
+func (s *FrameStream) Play(ctx context.Context, w io.Writer, fps float64) (err error) {
+	if fps <= 0 {
+		return fmt.Errorf("chafa: invalid frame rate %g", fps)
+	}
+
+	interval := time.Duration(float64(time.Second) / fps)
+	start := time.Now()
+	drawn := false
+
+	defer func() {
+		// Leave the cursor below the last frame
+		if drawn {
+			if _, werr := io.WriteString(w, "\n"); err == nil {
+				err = werr
+			}
+		}
+	}()
+
+	for i := 0; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := io.ReadFull(s.src, s.frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		// Always show the first frame, then skip any whose time has passed
+		due := start.Add(time.Duration(i) * interval)
+		if drawn && time.Since(due) >= interval {
+			s.dropped.Add(1)
+			continue
+		}
+
+		if err := s.draw(w, drawn); err != nil {
+			return err
+		}
+		drawn = true
+		s.rendered.Add(1)
+
+		next := time.NewTimer(time.Until(due.Add(interval)))
+		select {
+		case <-ctx.Done():
+			next.Stop()
+			return ctx.Err()
+		case <-next.C:
+		}
+	}
+}
+
+// Rendered returns the number of frames played so far. It may be called
+// while [FrameStream.Play] is running.
+func (s *FrameStream) Rendered() int {
+	return int(s.rendered.Load())
+}
+
+// Dropped returns the number of frames skipped so far to keep up with the
+// frame rate. It may be called while [FrameStream.Play] is running.
+func (s *FrameStream) Dropped() int {
+	return int(s.dropped.Load())
+}
+
+// draw renders the current frame and writes it, first moving the cursor back
+// over the previous frame if there is one.
+func (s *FrameStream) draw(w io.Writer, home bool) error {
+	s.renderer.mu.Lock()
+	out, err := s.renderer.renderPixelsLocked(s.frame, s.pixelType, s.width, s.height,
+		s.width*int32(pixelTypeBytes(s.pixelType)))
+	s.renderer.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if home {
+		out = cursorHome(s.termInfo, s.renderer) + out
+	}
+
+	_, err = io.WriteString(w, out)
+	return err
+}
@@ -0,0 +1,123 @@
+package chafa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// generateFrames returns a stream of n RGB8 frames of width x height pixels.
+func generateFrames(n, width, height int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		img := testImage(width, height, i*16)
+		for p := 0; p < len(img.Pix); p += 4 {
+			buf.Write(img.Pix[p : p+3])
+		}
+	}
+	return buf.Bytes()
+}
+
+// slowWriter discards everything written to it, taking delay for each write.
+type slowWriter struct {
+	delay time.Duration
+}
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func newTestFrameStream(t *testing.T, src io.Reader) *FrameStream {
+	t.Helper()
+
+	renderer, err := NewRenderer(WithGeometry(20, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { renderer.Close() })
+
+	s, err := NewFrameStream(src, 16, 8, CHAFA_PIXEL_RGB8, renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestFrameStreamPlaysEveryFrame(t *testing.T) {
+	requireLibrary(t)
+
+	const frames = 10
+	s := newTestFrameStream(t, bytes.NewReader(generateFrames(frames, 16, 8)))
+
+	var out bytes.Buffer
+	if err := s.Play(context.Background(), &out, 25); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Rendered() != frames || s.Dropped() != 0 {
+		t.Errorf("rendered %d and dropped %d frames, want %d and 0", s.Rendered(), s.Dropped(), frames)
+	}
+	if out.Len() == 0 {
+		t.Error("nothing was written")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.termInfo != nil {
+		t.Error("Close kept the TermInfo")
+	}
+}
+
+func TestFrameStreamDropsLateFrames(t *testing.T) {
+	requireLibrary(t)
+
+	const frames = 30
+	s := newTestFrameStream(t, bytes.NewReader(generateFrames(frames, 16, 8)))
+
+	// Read the counters while playing, as a progress display would
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for s.Rendered()+s.Dropped() < frames {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	// Writing takes 20ms, so at 500 fps most frames are late
+	if err := s.Play(context.Background(), slowWriter{20 * time.Millisecond}, 500); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	if s.Rendered()+s.Dropped() != frames {
+		t.Errorf("rendered %d and dropped %d frames, want %d in total", s.Rendered(), s.Dropped(), frames)
+	}
+	if s.Dropped() == 0 {
+		t.Error("no frames were dropped")
+	}
+	if s.Rendered() < 2 {
+		t.Errorf("rendered %d frames, want at least the first and some later ones", s.Rendered())
+	}
+}
+
+func TestFrameStreamTruncated(t *testing.T) {
+	requireLibrary(t)
+
+	data := generateFrames(3, 16, 8)
+	s := newTestFrameStream(t, bytes.NewReader(data[:len(data)-5]))
+
+	err := s.Play(context.Background(), io.Discard, 50)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Play returned %v, want io.ErrUnexpectedEOF", err)
+	}
+	if s.Rendered() != 2 {
+		t.Errorf("rendered %d frames, want 2", s.Rendered())
+	}
+}
@@ -0,0 +1,162 @@
+package chafa
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+// TermGeometry is the size of a terminal, as found by [TerminalGeometry].
+// Pixel sizes are zero if unknown.
+type TermGeometry struct {
+	// Size of the text area in character cells.
+	Width, Height int32
+
+	// Size of the text area in pixels.
+	WidthPx, HeightPx int32
+
+	// Size of a character cell in pixels.
+	CellWidth, CellHeight int32
+
+	// Width of a cell divided by its height, for [CalcCanvasGeometry]. It is
+	// 0.5 when the cell size is unknown.
+	FontRatio float32
+}
+
+// TerminalGeometry returns the size of the terminal f, which is usually
+// os.Stdout.
+//
+// The size is asked from the terminal driver (TIOCGWINSZ, or the console on
+// Windows). If that fails, the size in cells is taken from the COLUMNS and
+// LINES environment variables. Nothing is written to f, so the pixel sizes
+// are zero if the driver does not report them; [QueryTerminalGeometry] asks
+// the terminal itself for those.
+func TerminalGeometry(f *os.File) (TermGeometry, error) {
+	return terminalGeometry(f, 0)
+}
+
+// QueryTerminalGeometry is like [TerminalGeometry], but if the terminal
+// driver does not report the pixel size, it is queried with CSI 14t, 16t and
+// 18t, waiting up to timeout for a response.
+//
+// tty must be open for reading and writing, e.g. /dev/tty, and is put in raw
+// mode for the duration of the query, as with [Probe]. On Windows the query
+// goes through the console's CONIN$ and CONOUT$ handles instead.
+func QueryTerminalGeometry(tty *os.File, timeout time.Duration) (TermGeometry, error) {
+	return terminalGeometry(tty, timeout)
+}
+
+// terminalGeometry implements [TerminalGeometry], and queries f for missing
+// sizes if timeout is positive.
+func terminalGeometry(f *os.File, timeout time.Duration) (TermGeometry, error) {
+	var g TermGeometry
+	isTerminal := false
+
+	if width, height, widthPx, heightPx, err := windowSize(f); err == nil {
+		isTerminal = true
+		if width > 0 && height > 0 {
+			g.Width, g.Height = width, height
+		}
+		if widthPx > 0 && heightPx > 0 {
+			g.WidthPx, g.HeightPx = widthPx, heightPx
+		}
+	}
+
+	if g.Width <= 0 || g.Height <= 0 {
+		g.Width, g.Height = envInt32("COLUMNS"), envInt32("LINES")
+	}
+
+	if timeout > 0 && isTerminal && (g.WidthPx <= 0 || g.Width <= 0) {
+		queryGeometry(f, &g, timeout)
+	}
+
+	if g.Width <= 0 || g.Height <= 0 {
+		return TermGeometry{}, errors.New("chafa: cannot determine terminal size")
+	}
+
+	if g.WidthPx > 0 && g.HeightPx > 0 && g.CellWidth <= 0 {
+		g.CellWidth, g.CellHeight = g.WidthPx/g.Width, g.HeightPx/g.Height
+	}
+	if g.CellWidth > 0 && g.CellHeight > 0 && g.WidthPx <= 0 {
+		g.WidthPx, g.HeightPx = g.CellWidth*g.Width, g.CellHeight*g.Height
+	}
+
+	g.FontRatio = 0.5
+	if g.CellWidth > 0 && g.CellHeight > 0 {
+		g.FontRatio = float32(g.CellWidth) / float32(g.CellHeight)
+	}
+
+	return g, nil
+}
+
+// queryGeometry fills in the parts of g that are unknown by querying the
+// terminal tty, waiting up to timeout for the responses.
+func queryGeometry(tty *os.File, g *TermGeometry, timeout time.Duration) {
+	if err := Init(); err != nil {
+		return
+	}
+
+	termInfo := TermDbGetFallbackInfo(TermDbGetDefault())
+	defer TermInfoUnref(termInfo)
+
+	var queries []byte
+	for _, seq := range []TermSeq{
+		CHAFA_TERM_SEQ_QUERY_CELL_SIZE_PX,
+		CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_PX,
+		CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_CELLS,
+		CHAFA_TERM_SEQ_QUERY_PRIMARY_DEVICE_ATTRIBUTES,
+	} {
+		s, _ := formatSeq(termInfo, seq)
+		queries = append(queries, s...)
+	}
+
+	p, err := queryTerminal(tty, string(queries), termInfo, timeout)
+	if err != nil {
+		return
+	}
+
+	r := p.result
+	if g.Width <= 0 && r.Width > 0 && r.Height > 0 {
+		g.Width, g.Height = int32(r.Width), int32(r.Height)
+	}
+	if g.WidthPx <= 0 && r.WidthPx > 0 && r.HeightPx > 0 {
+		g.WidthPx, g.HeightPx = int32(r.WidthPx), int32(r.HeightPx)
+	}
+	if r.CellWidth > 0 && r.CellHeight > 0 {
+		g.CellWidth, g.CellHeight = int32(r.CellWidth), int32(r.CellHeight)
+	}
+}
+
+// envInt32 returns the value of the environment variable key as a number,
+// or 0 if it is not set to one.
+func envInt32(key string) int32 {
+	v, err := strconv.ParseInt(os.Getenv(key), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(v)
+}
+
+// FitToTerminal sets the geometry of config to the largest size that fits an
+// image of srcWidth x srcHeight pixels on a terminal of geometry g,
+// preserving its aspect ratio. One row is left free for the shell prompt.
+// The cell geometry is set too if it is known. It returns the canvas size in
+// cells.
+func FitToTerminal(config *CanvasConfig, g TermGeometry, srcWidth, srcHeight int32) (width, height int32) {
+	fontRatio := g.FontRatio
+	if fontRatio <= 0 {
+		fontRatio = 0.5
+	}
+
+	width, height = g.Width, max(g.Height-1, 1)
+	CalcCanvasGeometry(srcWidth, srcHeight, &width, &height, fontRatio, false, false)
+	width, height = max(width, 1), max(height, 1)
+
+	CanvasConfigSetGeometry(config, width, height)
+	if g.CellWidth > 0 && g.CellHeight > 0 {
+		CanvasConfigSetCellGeometry(config, g.CellWidth, g.CellHeight)
+	}
+
+	return width, height
+}
@@ -0,0 +1,123 @@
+package chafa
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestTerminalGeometryFallback(t *testing.T) {
+	master, slave := openPTY(t)
+
+	notTerminal, err := os.CreateTemp(t.TempDir(), "geometry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer notTerminal.Close()
+
+	tests := []struct {
+		name           string
+		f              *os.File
+		cols, rows     uint16
+		wpx, hpx       uint16
+		columns, lines string
+		want           TermGeometry
+		wantErr        bool
+	}{
+		{
+			name: "window size wins over the environment",
+			f:    slave, cols: 80, rows: 24, wpx: 800, hpx: 480,
+			columns: "100", lines: "30",
+			want: TermGeometry{Width: 80, Height: 24, WidthPx: 800, HeightPx: 480, CellWidth: 10, CellHeight: 20, FontRatio: 0.5},
+		},
+		{
+			name: "cells without pixels",
+			f:    slave, cols: 90, rows: 20,
+			columns: "100", lines: "30",
+			want: TermGeometry{Width: 90, Height: 20, FontRatio: 0.5},
+		},
+		{
+			name:    "empty window size falls back to the environment",
+			f:       slave,
+			columns: "100", lines: "30",
+			want: TermGeometry{Width: 100, Height: 30, FontRatio: 0.5},
+		},
+		{
+			name:    "not a terminal falls back to the environment",
+			f:       notTerminal,
+			columns: "120", lines: "40",
+			want: TermGeometry{Width: 120, Height: 40, FontRatio: 0.5},
+		},
+		{
+			name:    "no size anywhere",
+			f:       notTerminal,
+			wantErr: true,
+		},
+		{
+			name:    "incomplete environment",
+			f:       notTerminal,
+			columns: "120",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setWindowSize(t, master, tt.cols, tt.rows, tt.wpx, tt.hpx)
+			t.Setenv("COLUMNS", tt.columns)
+			t.Setenv("LINES", tt.lines)
+
+			g, err := TerminalGeometry(tt.f)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %+v, want an error", g)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g != tt.want {
+				t.Errorf("got %+v, want %+v", g, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalGeometryDoesNotQuery(t *testing.T) {
+	master, slave := openPTY(t)
+	setWindowSize(t, master, 80, 24, 0, 0)
+
+	if _, err := TerminalGeometry(slave); err != nil {
+		t.Fatal(err)
+	}
+
+	fds := []unix.PollFd{{Fd: int32(master.Fd()), Events: unix.POLLIN}}
+	if n, err := unix.Poll(fds, 50); err != nil || n != 0 {
+		t.Errorf("TerminalGeometry wrote to the terminal (poll = %d, %v)", n, err)
+	}
+}
+
+func TestQueryTerminalGeometry(t *testing.T) {
+	requireLibrary(t)
+
+	master, slave := openPTY(t)
+	setWindowSize(t, master, 80, 24, 0, 0)
+	queries := fakeTerminal(master, map[string]string{
+		"\x1b[16t": "\x1b[6;20;10t",
+		"\x1b[14t": "\x1b[4;480;800t",
+		"\x1b[0c":  "\x1b[?62;22c",
+	})
+
+	g, err := QueryTerminalGeometry(slave, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-queries
+
+	want := TermGeometry{Width: 80, Height: 24, WidthPx: 800, HeightPx: 480, CellWidth: 10, CellHeight: 20, FontRatio: 0.5}
+	if g != want {
+		t.Errorf("got %+v, want %+v", g, want)
+	}
+}
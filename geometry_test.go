@@ -0,0 +1,50 @@
+package chafa
+
+import "testing"
+
+func TestFitToTerminal(t *testing.T) {
+	requireLibrary(t)
+
+	tests := []struct {
+		name                string
+		g                   TermGeometry
+		srcWidth, srcHeight int32
+		width, height       int32
+	}{
+		// Square image in cells twice as tall as wide, limited by the rows
+		// left above the prompt
+		{"tall terminal", TermGeometry{Width: 80, Height: 24, CellWidth: 10, CellHeight: 20, FontRatio: 0.5}, 160, 160, 46, 23},
+		{"wide image", TermGeometry{Width: 80, Height: 24, FontRatio: 0.5}, 800, 100, 80, 5},
+		{"unknown font ratio", TermGeometry{Width: 80, Height: 24}, 160, 160, 46, 23},
+		{"one row", TermGeometry{Width: 80, Height: 1, FontRatio: 0.5}, 160, 160, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := CanvasConfigNew()
+			defer CanvasConfigUnref(config)
+			CanvasConfigSetCellGeometry(config, 7, 13)
+
+			width, height := FitToTerminal(config, tt.g, tt.srcWidth, tt.srcHeight)
+			if width != tt.width || height != tt.height {
+				t.Errorf("got %dx%d cells, want %dx%d", width, height, tt.width, tt.height)
+			}
+
+			var configWidth, configHeight int32
+			CanvasConfigGetGeometry(config, &configWidth, &configHeight)
+			if configWidth != width || configHeight != height {
+				t.Errorf("config geometry is %dx%d, returned %dx%d", configWidth, configHeight, width, height)
+			}
+
+			// The cell geometry is only replaced when it is known
+			wantCell := [2]int32{7, 13}
+			if tt.g.CellWidth > 0 {
+				wantCell = [2]int32{tt.g.CellWidth, tt.g.CellHeight}
+			}
+			var cell [2]int32
+			CanvasConfigGetCellGeometry(config, &cell[0], &cell[1])
+			if cell != wantCell {
+				t.Errorf("cell geometry is %v, want %v", cell, wantCell)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package chafa
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+)
+
+var (
+	// Installs a log handler for messages without a more specific handler,
+	// returning the previous one.
+	gLogSetDefaultHandler func(logFunc uintptr, userData unsafe.Pointer) uintptr
+
+	// Frees the memory allocated for gstr. If freeSegment is true the character
+	// data is freed too and nil is returned; otherwise it is returned and must be
+	// freed with g_free.
+	gStringFree func(gstr *GString, freeSegment bool) *byte
+
+	// Frees memory allocated by GLib. It is safe to pass nil.
+	gFree func(mem unsafe.Pointer)
+
+	// Frees a NULL-terminated array of strings, and the array itself.
+	gStrfreev func(strv unsafe.Pointer)
+
+	// Frees a GError and its message.
+	gErrorFree func(err *GError)
+
+	// Gets the string associated with a GQuark.
+	gQuarkToString func(quark uint32) string
+)
+
+// glibFuncs lists the GLib functions used by the package. They are optional:
+// if one cannot be found, the features relying on it are unavailable.
+var glibFuncs = []libraryFunc{
+	{&gLogSetDefaultHandler, "g_log_set_default_handler"},
+	{&gStringFree, "g_string_free"},
+	{&gFree, "g_free"},
+	{&gStrfreev, "g_strfreev"},
+	{&gErrorFree, "g_error_free"},
+	{&gQuarkToString, "g_quark_to_string"},
+}
+
+// glibAvailable records which of glibFuncs were found.
+var glibAvailable = make(map[string]bool)
+
+// bindGLib binds glibFuncs. GLib is a dependency of libchafa, so its symbols
+// are looked up through the libchafa handle first, which works wherever the
+// dynamic loader searches a library's dependencies. Otherwise GLib is opened
+// by name.
+func bindGLib(libchafa uintptr) {
+	var glib uintptr
+
+	for _, f := range glibFuncs {
+		sym, err := lookupSymbol(libchafa, f.name)
+		if err != nil || sym == 0 {
+			if glib == 0 {
+				glib = openGLib()
+			}
+			if glib != 0 {
+				sym, err = lookupSymbol(glib, f.name)
+			}
+		}
+		if err != nil || sym == 0 {
+			bindUnsupported(f.fptr, f.name)
+			continue
+		}
+		purego.RegisterFunc(f.fptr, sym)
+		glibAvailable[f.name] = true
+	}
+}
+
+// openGLib loads GLib by its usual file name, returning 0 if it is not found.
+func openGLib() uintptr {
+	var names []string
+	switch runtime.GOOS {
+	case "darwin":
+		names = []string{"libglib-2.0.0.dylib", "libglib-2.0.dylib"}
+	case "windows":
+		names = []string{"libglib-2.0-0.dll", "glib-2.0-0.dll"}
+	default:
+		names = []string{"libglib-2.0.so.0", "libglib-2.0.so"}
+	}
+
+	for _, name := range names {
+		if lib, err := openLibrary(name); err == nil {
+			return lib
+		}
+	}
+
+	return 0
+}
+
+// leakWarned records the GLib free functions that [warnLeak] warned about.
+var leakWarned sync.Map
+
+// warnLeak logs a warning, once per function, that memory is leaked because
+// the GLib function free could not be found.
+func warnLeak(free string) {
+	if _, warned := leakWarned.LoadOrStore(free, true); !warned {
+		logger().Warn("leaking memory allocated by libchafa", "missing", free)
+	}
+}
+
+// gStringFreeToGo copies the contents of gstr to a Go string and frees it. If
+// g_string_free could not be found, gstr is leaked rather than risk freeing it
+// with the wrong allocator, and a warning is logged.
+func gStringFreeToGo(gstr *GString) string {
+	if gstr == nil {
+		return ""
+	}
+
+	str := strings.Clone(gstr.str)
+	if glibAvailable["g_string_free"] {
+		gStringFree(gstr, true)
+	} else {
+		warnLeak("g_string_free")
+	}
+
+	return str
+}
+
+// gStrvFreeToGo copies the NULL-terminated string array strv to a slice of Go
+// strings and frees it.
+func gStrvFreeToGo(strv unsafe.Pointer) []string {
+	if strv == nil {
+		return nil
+	}
+
+	var strs []string
+	for i := uintptr(0); ; i++ {
+		p := *(**byte)(unsafe.Add(strv, i*unsafe.Sizeof(strv)))
+		if p == nil {
+			break
+		}
+		strs = append(strs, goString(p))
+	}
+
+	if glibAvailable["g_strfreev"] {
+		gStrfreev(strv)
+	} else {
+		warnLeak("g_strfreev")
+	}
+
+	return strs
+}
+
+// gErrorToGo converts gerr to an [*Error] and frees it. It returns nil if gerr is nil.
+func gErrorToGo(gerr *GError) error {
+	if gerr == nil {
+		return nil
+	}
+
+	err := &Error{Domain: gerr.Domain, Code: gerr.Code, Message: goString(gerr.Message)}
+	if glibAvailable["g_error_free"] {
+		gErrorFree(gerr)
+	} else {
+		warnLeak("g_error_free")
+	}
+
+	return err
+}
+
+// cString returns a NUL-terminated copy of s for passing to C.
+func cString(s string) *byte {
+	b := make([]byte, len(s)+1)
+	copy(b, s)
+
+	return &b[0]
+}
+
+// goString copies the NUL-terminated C string at p.
+func goString(p *byte) string {
+	if p == nil {
+		return ""
+	}
+
+	n := 0
+	for *(*byte)(unsafe.Add(unsafe.Pointer(p), n)) != 0 {
+		n++
+	}
+
+	return string(unsafe.Slice(p, n))
+}
@@ -0,0 +1,25 @@
+//go:build (darwin || linux || windows) && (amd64 || arm64)
+
+package chafa
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+)
+
+// installGLogHandler routes GLib's default log handler to logGLibMessage.
+func installGLogHandler() error {
+	if !glibAvailable["g_log_set_default_handler"] {
+		return errors.New("g_log_set_default_handler not found")
+	}
+
+	callback := purego.NewCallback(func(domain *byte, level uint32, message *byte, userData unsafe.Pointer) uintptr {
+		logGLibMessage(goString(domain), level, goString(message))
+		return 0
+	})
+	gLogSetDefaultHandler(callback, nil)
+
+	return nil
+}
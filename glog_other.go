@@ -0,0 +1,11 @@
+//go:build !((darwin || linux || windows) && (amd64 || arm64))
+
+package chafa
+
+import "errors"
+
+// installGLogHandler is not supported here, since purego callbacks are not
+// available on this platform.
+func installGLogHandler() error {
+	return errors.New("GLib log forwarding is not supported on this platform")
+}
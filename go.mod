@@ -2,4 +2,7 @@ module github.com/ploMP4/chafa-go
 
 go 1.24.3
 
-require github.com/ebitengine/purego v0.8.3
+require (
+	github.com/ebitengine/purego v0.8.3
+	golang.org/x/sys v0.33.0
+)
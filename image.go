@@ -17,6 +17,16 @@ var (
 	ImageSetFrame func(image *Image, frame *Frame)
 )
 
+// Creates a new [Image] like [ImageNew], but returns an error if the loaded
+// libchafa does not support images (it needs 1.14 or newer).
+func ImageNewGo() (*Image, error) {
+	if err := checkFunction("chafa_image_new"); err != nil {
+		return nil, err
+	}
+
+	return ImageNew(), nil
+}
+
 type Image struct {
 	Refs  int32
 	Frame *Frame
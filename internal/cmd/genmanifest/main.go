@@ -0,0 +1,247 @@
+// Command genmanifest generates embedded_manifest.go from libs/CHECKSUMS.txt
+// and libs/BUILD_INFO.txt, so the table of embedded libraries always matches
+// the binaries in the repository.
+//
+// It also writes one embed_<GOOS>_<GOARCH>.go file per platform, each
+// embedding only that platform's library, plus embed_noembed.go for builds
+// with the chafa_noembed tag and embed_unsupported.go for every other platform.
+//
+// Libraries must be stored as libs/<GOOS>-<GOARCH>/<file>. Run it from the
+// repository root, or through go generate. The -require flag takes a
+// comma-separated list of GOOS/GOARCH pairs that must all have a library, so a
+// release build that lost a platform fails instead of silently dropping it.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	checksumsFile   = "libs/CHECKSUMS.txt"
+	buildInfoFile   = "libs/BUILD_INFO.txt"
+	outputFile      = "embedded_manifest.go"
+	noEmbedFile     = "embed_noembed.go"
+	unsupportedFile = "embed_unsupported.go"
+	noEmbedTag      = "chafa_noembed"
+)
+
+type entry struct {
+	platform string
+	path     string
+	sum      string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genmanifest: ")
+
+	require := flag.String("require", "", "comma-separated `platforms` that must have a library")
+	flag.Parse()
+
+	entries, err := readChecksums(checksumsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if missing := missingPlatforms(entries, *require); len(missing) > 0 {
+		log.Fatalf("%s has no library for %s", checksumsFile, strings.Join(missing, ", "))
+	}
+
+	version, err := readChafaVersion(buildInfoFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := removeEmbedFiles(); err != nil {
+		log.Fatal(err)
+	}
+
+	var platforms []string
+	for _, e := range entries {
+		goos, goarch, _ := strings.Cut(e.platform, "/")
+		platforms = append(platforms, fmt.Sprintf("(%s && %s)", goos, goarch))
+
+		var buf bytes.Buffer
+		writeHeader(&buf)
+		fmt.Fprintf(&buf, "//go:build %s && %s && !%s\n\n", goos, goarch, noEmbedTag)
+		fmt.Fprintf(&buf, "package chafa\n\n")
+		fmt.Fprintf(&buf, "import \"embed\"\n\n")
+		fmt.Fprintf(&buf, "// embeddedLibs holds the library for %s only.\n", e.platform)
+		fmt.Fprintf(&buf, "//\n//go:embed %s\n", e.path)
+		fmt.Fprintf(&buf, "var embeddedLibs embed.FS\n\n")
+		fmt.Fprintf(&buf, "const embeddingDisabled = false\n")
+		if err := writeSource(fmt.Sprintf("embed_%s_%s.go", goos, goarch), &buf); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var noEmbed bytes.Buffer
+	writeHeader(&noEmbed)
+	fmt.Fprintf(&noEmbed, "//go:build %s\n\n", noEmbedTag)
+	fmt.Fprintf(&noEmbed, "package chafa\n\n")
+	fmt.Fprintf(&noEmbed, "import \"embed\"\n\n")
+	fmt.Fprintf(&noEmbed, "// embeddedLibs is empty, since embedding was turned off with the %s build tag.\n", noEmbedTag)
+	fmt.Fprintf(&noEmbed, "var embeddedLibs embed.FS\n\n")
+	fmt.Fprintf(&noEmbed, "const embeddingDisabled = true\n")
+	if err := writeSource(noEmbedFile, &noEmbed); err != nil {
+		log.Fatal(err)
+	}
+
+	var unsupported bytes.Buffer
+	writeHeader(&unsupported)
+	fmt.Fprintf(&unsupported, "//go:build !%s && !(%s)\n\n", noEmbedTag, strings.Join(platforms, " || "))
+	fmt.Fprintf(&unsupported, "package chafa\n\n")
+	fmt.Fprintf(&unsupported, "import \"embed\"\n\n")
+	fmt.Fprintf(&unsupported, "// embeddedLibs is empty, since there is no library for this platform.\n")
+	fmt.Fprintf(&unsupported, "var embeddedLibs embed.FS\n\n")
+	fmt.Fprintf(&unsupported, "const embeddingDisabled = false\n")
+	if err := writeSource(unsupportedFile, &unsupported); err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf)
+	fmt.Fprintf(&buf, "package chafa\n\n")
+	fmt.Fprintf(&buf, "// embeddedChafaVersion is the Chafa release the embedded libraries were built from.\n")
+	fmt.Fprintf(&buf, "const embeddedChafaVersion = %q\n\n", version)
+	fmt.Fprintf(&buf, "// embeddedPlatforms maps GOOS/GOARCH to the library embedded for that platform.\n")
+	fmt.Fprintf(&buf, "var embeddedPlatforms = map[string]embeddedPlatform{\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\t%q: {path: %q, sha256: %q},\n", e.platform, e.path, e.sum)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	if err := writeSource(outputFile, &buf); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeHeader(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "// Code generated by internal/cmd/genmanifest from %s; DO NOT EDIT.\n\n", checksumsFile)
+}
+
+// writeSource formats the Go source in buf and writes it to name.
+func writeSource(name string, buf *bytes.Buffer) error {
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return os.WriteFile(name, src, 0o644)
+}
+
+// removeEmbedFiles deletes previously generated embed files, so platforms
+// that were dropped from the checksums file do not linger.
+func removeEmbedFiles() error {
+	names, err := filepath.Glob("embed_*.go")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(data, []byte("// Code generated by internal/cmd/genmanifest")) {
+			continue
+		}
+		if err := os.Remove(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readChecksums parses a file in the format written by sha256sum.
+func readChecksums(name string) ([]entry, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	seen := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s: malformed line %q", name, scanner.Text())
+		}
+
+		sum := strings.ToLower(fields[0])
+		file := path.Clean(strings.TrimPrefix(strings.TrimPrefix(fields[1], "*"), "./"))
+
+		dir := path.Base(path.Dir(file))
+		goos, goarch, ok := strings.Cut(dir, "-")
+		if !ok || path.Dir(path.Dir(file)) != "libs" {
+			return nil, fmt.Errorf("%s: %s is not in a libs/<GOOS>-<GOARCH> directory", name, file)
+		}
+
+		platform := goos + "/" + goarch
+		if other, ok := seen[platform]; ok {
+			return nil, fmt.Errorf("%s: both %s and %s are for %s", name, other, file, platform)
+		}
+		seen[platform] = file
+
+		entries = append(entries, entry{platform: platform, path: file, sum: sum})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].platform < entries[j].platform
+	})
+
+	return entries, nil
+}
+
+// missingPlatforms returns the platforms in the comma-separated list required
+// that have no entry.
+func missingPlatforms(entries []entry, required string) []string {
+	have := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		have[e.platform] = true
+	}
+
+	var missing []string
+	for _, platform := range strings.Split(required, ",") {
+		if platform = strings.TrimSpace(platform); platform != "" && !have[platform] {
+			missing = append(missing, platform)
+		}
+	}
+
+	return missing
+}
+
+// readChafaVersion returns the "Chafa Version" field of the build info file.
+func readChafaVersion(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == "Chafa Version" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: no Chafa Version", name)
+}
@@ -0,0 +1,121 @@
+package chafa
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	layoutOnce sync.Once
+	layoutErr  error
+)
+
+// CheckLayout reports whether the fields of [CanvasConfig], [SymbolMap] and
+// [TermInfo] can be read directly with the loaded libchafa. These structs
+// mirror private C layouts of Chafa 1.16, which other releases may change.
+//
+// The check sets values through the library's setters and compares them with
+// the struct fields, once per process. It returns the error [Init] reported,
+// or an error matching errors.ErrUnsupported naming the first field that does
+// not match. The getter and setter functions work regardless.
+func CheckLayout() error {
+	layoutOnce.Do(func() {
+		if layoutErr = Init(); layoutErr != nil {
+			return
+		}
+		if field := mismatchedField(); field != "" {
+			layoutErr = fmt.Errorf("chafa: layout of %s does not match the loaded libchafa (version %s): %w",
+				field, Version(), errors.ErrUnsupported)
+			logger().Warn("struct layout does not match the loaded libchafa", "field", field)
+		}
+	})
+
+	return layoutErr
+}
+
+// mismatchedField returns the first mirrored struct field found not to match
+// the loaded library, or an empty string if all of them do.
+func mismatchedField() string {
+	config := CanvasConfigNew()
+	defer CanvasConfigUnref(config)
+
+	CanvasConfigSetGeometry(config, 7, 5)
+	CanvasConfigSetCellGeometry(config, 9, 13)
+	CanvasConfigSetWorkFactor(config, 0.25)
+	CanvasConfigSetPreprocessingEnabled(config, false)
+	CanvasConfigSetFgOnlyEnabled(config, true)
+	CanvasConfigSetOptimizations(config, CHAFA_OPTIMIZATION_REPEAT_CELLS)
+
+	switch {
+	case config.Refs != 1:
+		return "CanvasConfig.Refs"
+	case config.Width != 7 || config.Height != 5:
+		return "CanvasConfig.Width"
+	case config.CellWidth != 9 || config.CellHeight != 13:
+		return "CanvasConfig.CellWidth"
+	case config.WorkFactor != CanvasConfigGetWorkFactor(config):
+		return "CanvasConfig.WorkFactor"
+	case config.SymbolMap.Refs != 1 || config.FillSymbolMap.Refs != 1:
+		return "CanvasConfig.SymbolMap"
+	case config.Flags&3 != 2:
+		return "CanvasConfig.Flags"
+	case config.Optimizations != CHAFA_OPTIMIZATION_REPEAT_CELLS:
+		return "CanvasConfig.Optimizations"
+	}
+
+	if HasFunction("chafa_canvas_config_set_passthrough") {
+		CanvasConfigSetPassthrough(config, CHAFA_PASSTHROUGH_TMUX)
+		if config.Passthrough != CHAFA_PASSTHROUGH_TMUX {
+			return "CanvasConfig.Passthrough"
+		}
+	}
+
+	symbolMap := SymbolMapNew()
+	defer SymbolMapUnref(symbolMap)
+
+	if symbolMap.Refs != 1 {
+		return "SymbolMap.Refs"
+	}
+	if HasFunction("chafa_symbol_map_set_allow_builtin_glyphs") {
+		SymbolMapSetAllowBuiltinGlyphs(symbolMap, false)
+		builtin := symbolMap.Flags&2 != 0
+		SymbolMapSetAllowBuiltinGlyphs(symbolMap, true)
+		if builtin || symbolMap.Flags&2 == 0 {
+			return "SymbolMap.Flags"
+		}
+	}
+
+	termInfo := TermInfoNew()
+	defer TermInfoUnref(termInfo)
+
+	TermInfoSetName(termInfo, "chafa-go")
+	switch {
+	case termInfo.Refs != 1:
+		return "TermInfo.Refs"
+	case goString(termInfo.Name) != "chafa-go":
+		return "TermInfo.Name"
+	}
+
+	// Both arguments are preceded by two bytes, and the second is at index 0
+	if TermInfoSetSeq(termInfo, CHAFA_TERM_SEQ_CURSOR_TO_POS, "\x1b[%2;%1H") != nil {
+		return "TermInfo.SeqArgs"
+	}
+	args := termInfo.SeqArgs[CHAFA_TERM_SEQ_CURSOR_TO_POS]
+	if args[0] != (SeqArgInfo{Flags: 2 << 1, ArgIndex: 1}) || args[1] != (SeqArgInfo{Flags: 1 << 1, ArgIndex: 0}) {
+		return "TermInfo.SeqArgs"
+	}
+	if goString(termInfo.UnparsedStr[CHAFA_TERM_SEQ_CURSOR_TO_POS]) != "\x1b[%2;%1H" {
+		return "TermInfo.UnparsedStr"
+	}
+
+	if HasFunction("chafa_term_info_set_quirks") && HasFunction("chafa_term_info_set_safe_symbol_tags") {
+		TermInfoSetQuirks(termInfo, CHAFA_TERM_QUIRK_SIXEL_OVERSHOOT)
+		TermInfoSetSafeSymbolTags(termInfo, CHAFA_SYMBOL_TAG_BLOCK)
+		if termInfo.Quirks != CHAFA_TERM_QUIRK_SIXEL_OVERSHOOT || termInfo.SafeSymbolTags != CHAFA_SYMBOL_TAG_BLOCK {
+			return "TermInfo.Quirks"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,318 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// LibraryEnv is the environment variable that, when set, names the libchafa
+// shared library to load. It takes precedence over every [LibrarySource]
+// except a path given with [SetLibraryPath].
+const LibraryEnv = "CHAFA_GO_LIBRARY"
+
+// LibrarySource is a place libchafa can be loaded from.
+type LibrarySource int32
+
+const (
+	// An explicit path, set with [SetLibraryPath] or [LibraryEnv]. When a path
+	// is set it is always tried first, and failing to load it is an error.
+	LibrarySourceExplicit LibrarySource = 0
+
+	// The copy of libchafa embedded in this package, extracted at runtime.
+	LibrarySourceEmbedded LibrarySource = 1
+
+	// The system library, looked up by name (e.g. libchafa.so.0) using the
+	// dynamic loader's normal search rules.
+	LibrarySourceSystem LibrarySource = 2
+
+	// A search of the library path environment variable (LD_LIBRARY_PATH,
+	// DYLD_LIBRARY_PATH or PATH), the pkg-config libdir, well-known install
+	// prefixes such as /usr/lib64 and Homebrew's, and finally the working directory.
+	LibrarySourceSearchPaths LibrarySource = 3
+)
+
+func (s LibrarySource) String() string {
+	switch s {
+	case LibrarySourceExplicit:
+		return "explicit"
+	case LibrarySourceEmbedded:
+		return "embedded"
+	case LibrarySourceSystem:
+		return "system"
+	case LibrarySourceSearchPaths:
+		return "search-paths"
+	default:
+		return fmt.Sprintf("LibrarySource(%d)", int32(s))
+	}
+}
+
+// LibraryPolicy is the order in which library sources are tried. The first
+// library that loads successfully is used.
+type LibraryPolicy []LibrarySource
+
+var (
+	// Prefer the embedded library, falling back to the system one. This is the default.
+	LibraryPolicyEmbeddedFirst = LibraryPolicy{
+		LibrarySourceEmbedded,
+		LibrarySourceSystem,
+		LibrarySourceSearchPaths,
+	}
+
+	// Prefer a system-wide libchafa, as distribution packages usually want,
+	// falling back to the embedded library.
+	LibraryPolicySystemFirst = LibraryPolicy{
+		LibrarySourceSystem,
+		LibrarySourceSearchPaths,
+		LibrarySourceEmbedded,
+	}
+
+	// Only use a system-wide libchafa.
+	LibraryPolicySystemOnly = LibraryPolicy{
+		LibrarySourceSystem,
+		LibrarySourceSearchPaths,
+	}
+)
+
+// LibraryAttempt records one try at loading libchafa.
+type LibraryAttempt struct {
+	Source LibrarySource
+	Path   string
+	Err    error
+}
+
+// LibraryReport describes how libchafa was located.
+type LibraryReport struct {
+	// Path and Source of the library that was loaded. Path is empty if no
+	// library has been loaded.
+	Path   string
+	Source LibrarySource
+
+	// Every attempt made, in order, including the successful one.
+	Attempts []LibraryAttempt
+}
+
+// ErrLibraryAlreadyLoaded is returned when the library configuration is
+// changed after libchafa has been loaded.
+var ErrLibraryAlreadyLoaded = errors.New("chafa: library already loaded")
+
+var (
+	libConfigMu   sync.Mutex
+	libPath       string
+	libPolicy     = LibraryPolicyEmbeddedFirst
+	libReport     LibraryReport
+	libLoadedOnce bool
+)
+
+// SetLibraryPath makes [Init] load libchafa from path instead of searching
+// for it. It must be called before the library is loaded.
+func SetLibraryPath(path string) error {
+	libConfigMu.Lock()
+	defer libConfigMu.Unlock()
+
+	if libLoadedOnce {
+		return ErrLibraryAlreadyLoaded
+	}
+	libPath = path
+
+	return nil
+}
+
+// SetLibraryPolicy sets the order in which library sources are searched.
+// It must be called before the library is loaded.
+func SetLibraryPolicy(policy LibraryPolicy) error {
+	libConfigMu.Lock()
+	defer libConfigMu.Unlock()
+
+	if libLoadedOnce {
+		return ErrLibraryAlreadyLoaded
+	}
+	libPolicy = append(LibraryPolicy(nil), policy...)
+
+	return nil
+}
+
+// GetLibraryReport returns a report of where libchafa was loaded from, or of
+// the failed attempts if it could not be loaded.
+func GetLibraryReport() LibraryReport {
+	libConfigMu.Lock()
+	defer libConfigMu.Unlock()
+
+	report := libReport
+	report.Attempts = append([]LibraryAttempt(nil), libReport.Attempts...)
+
+	return report
+}
+
+func loadLibrary() (uintptr, error) {
+	libConfigMu.Lock()
+	defer libConfigMu.Unlock()
+
+	libLoadedOnce = true
+	report := &libReport
+
+	path := libPath
+	if path == "" {
+		path = os.Getenv(LibraryEnv)
+	}
+	if path != "" {
+		lib, err := openLibrary(path)
+		report.Attempts = append(report.Attempts, LibraryAttempt{LibrarySourceExplicit, path, err})
+		if err != nil {
+			return 0, fmt.Errorf("failed to load library at %s: %w", path, err)
+		}
+		report.Path, report.Source = path, LibrarySourceExplicit
+		return lib, nil
+	}
+
+	for _, source := range libPolicy {
+		for _, candidate := range libraryCandidates(source) {
+			if candidate.prepare != nil {
+				candidate.prepare(&candidate)
+			}
+			if candidate.err == nil {
+				var lib uintptr
+				lib, candidate.err = openLibrary(candidate.path)
+				if candidate.release != nil {
+					candidate.release()
+				}
+				if candidate.err == nil {
+					report.Attempts = append(report.Attempts, LibraryAttempt{source, candidate.path, nil})
+					report.Path, report.Source = candidate.path, source
+					logger().Debug("loaded libchafa", "source", source, "path", candidate.path)
+					return lib, nil
+				}
+			}
+
+			report.Attempts = append(report.Attempts, LibraryAttempt{source, candidate.path, candidate.err})
+			level := slog.LevelDebug
+			if source == LibrarySourceEmbedded {
+				level = slog.LevelWarn
+			}
+			logger().Log(context.Background(), level, "failed to load libchafa",
+				"source", source, "path", candidate.path, "err", candidate.err)
+		}
+	}
+
+	return 0, fmt.Errorf("libchafa not found (tried %d locations)", len(report.Attempts))
+}
+
+type libraryCandidate struct {
+	path string
+	err  error
+
+	// release, if set, is called once the candidate has been tried.
+	release func()
+
+	// prepare, if set, fills in the other fields right before the candidate
+	// is tried, so that costly preparation is skipped if an earlier one loads.
+	prepare func(*libraryCandidate)
+}
+
+// libraryCandidates lists the paths to try for source. A candidate with an
+// error could not be prepared and is only recorded in the report.
+func libraryCandidates(source LibrarySource) []libraryCandidate {
+	var candidates []libraryCandidate
+
+	switch source {
+	case LibrarySourceEmbedded:
+		candidates = embeddedCandidates()
+
+	case LibrarySourceSystem:
+		for _, name := range libraryNames() {
+			candidates = append(candidates, libraryCandidate{path: name})
+		}
+
+	case LibrarySourceSearchPaths:
+		for _, dir := range librarySearchDirs() {
+			for _, name := range libraryNames() {
+				path := filepath.Join(dir, name)
+				if _, err := os.Stat(path); err == nil {
+					candidates = append(candidates, libraryCandidate{path: path})
+				}
+			}
+		}
+	}
+
+	return candidates
+}
+
+// libraryNames returns the file names libchafa is installed under on the
+// current platform, most specific first.
+func libraryNames() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"libchafa.0.dylib", "libchafa.dylib"}
+	case "windows":
+		return []string{"libchafa-0.dll", "libchafa.dll"}
+	default:
+		return []string{"libchafa.so.0", "libchafa.so"}
+	}
+}
+
+// librarySearchDirs returns the directories searched by [LibrarySourceSearchPaths].
+func librarySearchDirs() []string {
+	var dirs []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		dirs = append(dirs, filepath.SplitList(os.Getenv("DYLD_LIBRARY_PATH"))...)
+		dirs = append(dirs, filepath.SplitList(os.Getenv("DYLD_FALLBACK_LIBRARY_PATH"))...)
+	case "windows":
+		dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+	default:
+		dirs = append(dirs, filepath.SplitList(os.Getenv("LD_LIBRARY_PATH"))...)
+	}
+
+	if out, err := exec.Command("pkg-config", "--variable=libdir", "chafa").Output(); err == nil {
+		dirs = append(dirs, strings.TrimSpace(string(out)))
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		dirs = append(dirs, "/opt/homebrew/lib", "/usr/local/lib", "/opt/local/lib")
+	case "linux", "freebsd":
+		dirs = append(dirs, "/usr/local/lib", "/usr/local/lib64", "/usr/lib64", "/usr/lib")
+		if triplet := multiarchTriplet(); triplet != "" {
+			dirs = append(dirs, filepath.Join("/usr/lib", triplet))
+		}
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, cwd)
+	}
+
+	// Drop empty entries and duplicates, keeping the first occurrence.
+	seen := make(map[string]bool, len(dirs))
+	unique := dirs[:0]
+	for _, dir := range dirs {
+		if dir != "" && !seen[dir] {
+			seen[dir] = true
+			unique = append(unique, dir)
+		}
+	}
+
+	return unique
+}
+
+// multiarchTriplet returns the Debian multiarch directory name for the
+// current architecture.
+func multiarchTriplet() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "x86_64-linux-gnu"
+	case "arm64":
+		return "aarch64-linux-gnu"
+	case "386":
+		return "i386-linux-gnu"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,95 @@
+package chafa
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	libLogger atomic.Pointer[slog.Logger]
+
+	// Guards installing the GLib log handler, which needs both the library
+	// to be loaded and a logger to be set.
+	logMu        sync.Mutex
+	logSet       bool
+	logLibLoaded bool
+	logInstalled bool
+)
+
+func init() {
+	libLogger.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetLogger sets the logger for the package's diagnostics, such as library
+// sources that failed to load. By default nothing is logged. Passing nil
+// restores the default.
+//
+// Once a logger is set, messages logged by libchafa and GLib through g_log
+// are also sent to it, where supported (64-bit x86 and ARM). Note that this
+// replaces GLib's default log handler for the whole process.
+func SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	libLogger.Store(logger)
+
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	logSet = true
+	installLogHandlerLocked()
+}
+
+// logger returns the logger set with [SetLogger].
+func logger() *slog.Logger {
+	return libLogger.Load()
+}
+
+// libraryLoaded is called by [Init] once the library is bound.
+func libraryLoaded() {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	logLibLoaded = true
+	installLogHandlerLocked()
+}
+
+func installLogHandlerLocked() {
+	if !logSet || !logLibLoaded || logInstalled {
+		return
+	}
+	logInstalled = true
+
+	if err := installGLogHandler(); err != nil {
+		logger().Debug("not forwarding GLib log messages", "err", err)
+	}
+}
+
+// GLib log level flags.
+const (
+	gLogLevelError    = 1 << 2
+	gLogLevelCritical = 1 << 3
+	gLogLevelWarning  = 1 << 4
+	gLogLevelMessage  = 1 << 5
+	gLogLevelInfo     = 1 << 6
+	gLogLevelDebug    = 1 << 7
+)
+
+// logGLibMessage forwards one message from g_log to the logger.
+func logGLibMessage(domain string, level uint32, message string) {
+	var slogLevel slog.Level
+	switch {
+	case level&(gLogLevelError|gLogLevelCritical) != 0:
+		slogLevel = slog.LevelError
+	case level&gLogLevelWarning != 0:
+		slogLevel = slog.LevelWarn
+	case level&(gLogLevelMessage|gLogLevelInfo) != 0:
+		slogLevel = slog.LevelInfo
+	default:
+		slogLevel = slog.LevelDebug
+	}
+
+	logger().Log(context.Background(), slogLevel, message, "domain", domain, "source", "glib")
+}
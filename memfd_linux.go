@@ -0,0 +1,50 @@
+//go:build linux
+
+package chafa
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// memfdEmbeddedLibrary copies the embedded library into a sealed anonymous
+// memory file and returns a path the dynamic loader can open it by. The
+// returned release function closes the file; the loader keeps its own mapping.
+func memfdEmbeddedLibrary() (path string, release func(), err error) {
+	libName, data, _, err := embeddedLibrary()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Kernels with vm.memfd_noexec set require MFD_EXEC to map the file as
+	// executable, but kernels older than 6.3 reject the flag.
+	flags := unix.MFD_CLOEXEC | unix.MFD_ALLOW_SEALING
+	fd, err := unix.MemfdCreate(libName, flags|unix.MFD_EXEC)
+	if errors.Is(err, unix.EINVAL) {
+		fd, err = unix.MemfdCreate(libName, flags)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("memfd_create: %w", err)
+	}
+
+	release = func() { unix.Close(fd) }
+
+	for written := 0; written < len(data); {
+		n, err := unix.Write(fd, data[written:])
+		if err != nil {
+			release()
+			return "", nil, fmt.Errorf("failed to write library to memfd: %w", err)
+		}
+		written += n
+	}
+
+	seals := unix.F_SEAL_SEAL | unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE
+	if _, err := unix.FcntlInt(uintptr(fd), unix.F_ADD_SEALS, seals); err != nil {
+		release()
+		return "", nil, fmt.Errorf("failed to seal memfd: %w", err)
+	}
+
+	return fmt.Sprintf("/proc/self/fd/%d", fd), release, nil
+}
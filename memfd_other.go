@@ -0,0 +1,13 @@
+//go:build !linux
+
+package chafa
+
+import (
+	"errors"
+	"runtime"
+)
+
+// memfdEmbeddedLibrary is only supported on Linux.
+func memfdEmbeddedLibrary() (path string, release func(), err error) {
+	return "", nil, errors.New("loading from memory is not supported on " + runtime.GOOS)
+}
@@ -1,5 +1,7 @@
 package chafa
 
+import "unsafe"
+
 // Calculates an optimal geometry for a [Canvas] given the width and height
 // of an input image, maximum width and height of the canvas, font ratio, zoom and
 // stretch preferences.
@@ -19,3 +21,7 @@ var CalcCanvasGeometry func(
 	fontRatio float32,
 	zoom, stretch bool,
 )
+
+// Frees a GString array allocated by libchafa, such as the one returned by
+// [CanvasPrintRows]. The array must be NULL-terminated.
+var FreeGStringArray func(gstrs unsafe.Pointer)
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package chafa
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivate returns an error if the file described by info is not owned
+// by the current user, or can be written to by other users.
+func checkPrivate(info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("cannot determine owner of %s", info.Name())
+	}
+	if int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("owned by uid %d, not %d", stat.Uid, os.Getuid())
+	}
+	if info.Mode().Perm()&0o022 != 0 {
+		return errors.New("writable by other users")
+	}
+
+	return nil
+}
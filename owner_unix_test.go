@@ -0,0 +1,77 @@
+//go:build !windows
+
+package chafa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakePrivateDir(t *testing.T) {
+	base := t.TempDir()
+
+	dir := filepath.Join(base, "new")
+	if err := makePrivateDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("created with mode %v, want no access for others", perm)
+	}
+
+	// An existing private directory is reused
+	if err := makePrivateDir(dir); err != nil {
+		t.Errorf("existing private directory: %v", err)
+	}
+
+	for _, mode := range []os.FileMode{0o777, 0o770, 0o702} {
+		loose := filepath.Join(base, "loose"+mode.String())
+		if err := os.Mkdir(loose, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(loose, mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := makePrivateDir(loose); err == nil {
+			t.Errorf("accepted a directory with mode %v", mode)
+		}
+	}
+
+	file := filepath.Join(base, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := makePrivateDir(file); err == nil {
+		t.Error("accepted a regular file")
+	}
+
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := makePrivateDir(link); err == nil {
+		t.Error("accepted a symlink to a private directory")
+	}
+}
+
+func TestMakePrivateDirOtherOwner(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing the owner of a directory needs root")
+	}
+
+	dir := filepath.Join(t.TempDir(), "other")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(dir, 65534, 65534); err != nil {
+		t.Skipf("cannot change owner: %v", err)
+	}
+
+	if err := makePrivateDir(dir); err == nil {
+		t.Error("accepted a directory owned by another user")
+	}
+}
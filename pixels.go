@@ -0,0 +1,150 @@
+package chafa
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+)
+
+// imagePixels returns the pixel data of img in a layout libchafa understands.
+//
+// Images backed by a buffer with a matching [PixelType] (*image.RGBA and
+// *image.NRGBA) are passed through without copying. Other common types are
+// converted directly to the most compact matching format, and anything else
+// is drawn into an *image.RGBA.
+func imagePixels(img image.Image) (pixels []uint8, pixelType PixelType, width, height, rowstride int32) {
+	bounds := img.Bounds()
+	width, height = int32(bounds.Dx()), int32(bounds.Dy())
+
+	switch img := img.(type) {
+	case *image.RGBA:
+		return img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y):],
+			CHAFA_PIXEL_RGBA8_PREMULTIPLIED, width, height, int32(img.Stride)
+
+	case *image.NRGBA:
+		return img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y):],
+			CHAFA_PIXEL_RGBA8_UNASSOCIATED, width, height, int32(img.Stride)
+
+	case *image.Gray:
+		pixels = make([]uint8, bounds.Dx()*bounds.Dy()*3)
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			row := img.Pix[img.PixOffset(bounds.Min.X, y):][:bounds.Dx()]
+			for _, v := range row {
+				pixels[i], pixels[i+1], pixels[i+2] = v, v, v
+				i += 3
+			}
+		}
+		return pixels, CHAFA_PIXEL_RGB8, width, height, width * 3
+
+	case *image.YCbCr:
+		pixels = make([]uint8, bounds.Dx()*bounds.Dy()*3)
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				yi, ci := img.YOffset(x, y), img.COffset(x, y)
+				pixels[i], pixels[i+1], pixels[i+2] = color.YCbCrToRGB(img.Y[yi], img.Cb[ci], img.Cr[ci])
+				i += 3
+			}
+		}
+		return pixels, CHAFA_PIXEL_RGB8, width, height, width * 3
+
+	case *image.Paletted:
+		var lut [256][4]uint8
+		for i, c := range img.Palette {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			lut[i] = [4]uint8{n.R, n.G, n.B, n.A}
+		}
+
+		pixels = make([]uint8, bounds.Dx()*bounds.Dy()*4)
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			row := img.Pix[img.PixOffset(bounds.Min.X, y):][:bounds.Dx()]
+			for _, idx := range row {
+				copy(pixels[i:i+4], lut[idx][:])
+				i += 4
+			}
+		}
+		return pixels, CHAFA_PIXEL_RGBA8_UNASSOCIATED, width, height, width * 4
+
+	default:
+		rgba := image.NewRGBA(bounds)
+		draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+		return rgba.Pix, CHAFA_PIXEL_RGBA8_PREMULTIPLIED, width, height, int32(rgba.Stride)
+	}
+}
+
+// imagePixelsCopy is like imagePixels, but the pixel data never shares memory
+// with img.
+func imagePixelsCopy(img image.Image) (pixels []uint8, pixelType PixelType, width, height, rowstride int32) {
+	pixels, pixelType, width, height, rowstride = imagePixels(img)
+
+	switch img.(type) {
+	case *image.RGBA, *image.NRGBA:
+		n := int(rowstride)*int(height-1) + int(width)*4
+		pixels = slices.Clone(pixels[:n])
+	}
+
+	return pixels, pixelType, width, height, rowstride
+}
+
+// downscalePixels shrinks an image in pixelType, one of the 8-bit RGB(A)
+// formats returned by imagePixels, to dstWidth x dstHeight with a box filter.
+// Every source pixel contributes to exactly one destination pixel.
+func downscalePixels(
+	pixels []uint8,
+	pixelType PixelType,
+	width, height, rowstride int32,
+	dstWidth, dstHeight int32,
+) (dst []uint8, dstRowstride int32) {
+	bpp := pixelTypeBytes(pixelType)
+
+	dstRowstride = dstWidth * int32(bpp)
+	dst = make([]uint8, int(dstRowstride)*int(dstHeight))
+	sums := make([]uint64, int(dstWidth)*bpp)
+	counts := make([]uint64, dstWidth)
+
+	y0 := int32(0)
+	for dy := int32(0); dy < dstHeight; dy++ {
+		y1 := int32(int64(dy+1) * int64(height) / int64(dstHeight))
+
+		clear(sums)
+		clear(counts)
+		for y := y0; y < y1; y++ {
+			row := pixels[int(y)*int(rowstride):]
+			x0 := int32(0)
+			for dx := int32(0); dx < dstWidth; dx++ {
+				x1 := int32(int64(dx+1) * int64(width) / int64(dstWidth))
+				sum := sums[int(dx)*bpp:][:bpp]
+				for x := x0; x < x1; x++ {
+					px := row[int(x)*bpp:][:bpp]
+					for c := range px {
+						sum[c] += uint64(px[c])
+					}
+				}
+				counts[dx] += uint64(x1 - x0)
+				x0 = x1
+			}
+		}
+
+		out := dst[int(dy)*int(dstRowstride):]
+		for dx := range counts {
+			n := max(counts[dx], 1)
+			for c := 0; c < bpp; c++ {
+				out[dx*bpp+c] = uint8(sums[dx*bpp+c] / n)
+			}
+		}
+		y0 = y1
+	}
+
+	return dst, dstRowstride
+}
+
+// pixelTypeBytes returns the number of bytes used by one pixel of pixelType.
+func pixelTypeBytes(pixelType PixelType) int {
+	if pixelType == CHAFA_PIXEL_RGB8 || pixelType == CHAFA_PIXEL_BGR8 {
+		return 3
+	}
+	return 4
+}
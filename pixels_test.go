@@ -0,0 +1,166 @@
+package chafa
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// pixelsToNRGBA converts pixels returned by imagePixels to an *image.NRGBA.
+func pixelsToNRGBA(t *testing.T, pixels []uint8, pixelType PixelType, width, height, rowstride int32) *image.NRGBA {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, int(width), int(height)))
+	for y := 0; y < int(height); y++ {
+		row := pixels[y*int(rowstride):]
+		for x := 0; x < int(width); x++ {
+			var c color.Color
+			switch pixelType {
+			case CHAFA_PIXEL_RGB8:
+				p := row[x*3:]
+				c = color.NRGBA{p[0], p[1], p[2], 0xff}
+			case CHAFA_PIXEL_RGBA8_UNASSOCIATED:
+				p := row[x*4:]
+				c = color.NRGBA{p[0], p[1], p[2], p[3]}
+			case CHAFA_PIXEL_RGBA8_PREMULTIPLIED:
+				p := row[x*4:]
+				c = color.RGBA{p[0], p[1], p[2], p[3]}
+			default:
+				t.Fatalf("unexpected pixel type %d", pixelType)
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+// drawNRGBA draws img into a new *image.NRGBA with its origin at 0, 0.
+func drawNRGBA(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// fillImage sets every pixel of img to a color that varies with its position,
+// with partial transparency if withAlpha is true.
+func fillImage(img draw.Image, withAlpha bool) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBA{uint8(x * 17), uint8(y * 29), uint8(x*y + 7), 0xff}
+			if withAlpha {
+				c.A = uint8(x*40 + y*8)
+			}
+			img.Set(x, y, c)
+		}
+	}
+}
+
+func newYCbCr(r image.Rectangle, ratio image.YCbCrSubsampleRatio) *image.YCbCr {
+	img := image.NewYCbCr(r, ratio)
+	for i := range img.Y {
+		img.Y[i] = uint8(i * 7)
+	}
+	for i := range img.Cb {
+		img.Cb[i] = uint8(i*13 + 64)
+		img.Cr[i] = uint8(200 - i*11)
+	}
+	return img
+}
+
+func TestImagePixels(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(-2, -1, 7, 6))
+	fillImage(rgba, true)
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 9, 7))
+	fillImage(nrgba, true)
+	gray := image.NewGray(image.Rect(0, 0, 9, 7))
+	fillImage(gray, false)
+	cmyk := image.NewCMYK(image.Rect(0, 0, 5, 4))
+	fillImage(cmyk, false)
+
+	paletted := image.NewPaletted(image.Rect(1, 1, 10, 8), color.Palette{
+		color.NRGBA{0xff, 0, 0, 0xff},
+		color.NRGBA{0, 0xff, 0, 0x80},
+		color.RGBA{0, 0, 0x40, 0x40},
+		color.Gray{0x99},
+	})
+	for i := range paletted.Pix {
+		paletted.Pix[i] = uint8(i % 4)
+	}
+
+	tests := []struct {
+		name      string
+		img       image.Image
+		pixelType PixelType
+		zeroCopy  bool
+	}{
+		{"RGBA", rgba, CHAFA_PIXEL_RGBA8_PREMULTIPLIED, true},
+		{"RGBA sub-image", rgba.SubImage(image.Rect(0, 1, 5, 4)), CHAFA_PIXEL_RGBA8_PREMULTIPLIED, true},
+		{"NRGBA", nrgba, CHAFA_PIXEL_RGBA8_UNASSOCIATED, true},
+		{"NRGBA sub-image", nrgba.SubImage(image.Rect(3, 2, 8, 7)), CHAFA_PIXEL_RGBA8_UNASSOCIATED, true},
+		{"Gray", gray, CHAFA_PIXEL_RGB8, false},
+		{"Gray sub-image", gray.SubImage(image.Rect(1, 1, 4, 6)), CHAFA_PIXEL_RGB8, false},
+		{"YCbCr 4:4:4", newYCbCr(image.Rect(0, 0, 9, 7), image.YCbCrSubsampleRatio444), CHAFA_PIXEL_RGB8, false},
+		{"YCbCr 4:2:2", newYCbCr(image.Rect(0, 0, 9, 7), image.YCbCrSubsampleRatio422), CHAFA_PIXEL_RGB8, false},
+		{"YCbCr 4:2:0", newYCbCr(image.Rect(0, 0, 9, 7), image.YCbCrSubsampleRatio420), CHAFA_PIXEL_RGB8, false},
+		{"YCbCr sub-image", newYCbCr(image.Rect(0, 0, 9, 7), image.YCbCrSubsampleRatio420).SubImage(image.Rect(1, 1, 8, 6)), CHAFA_PIXEL_RGB8, false},
+		{"Paletted", paletted, CHAFA_PIXEL_RGBA8_UNASSOCIATED, false},
+		{"Paletted sub-image", paletted.SubImage(image.Rect(2, 3, 9, 8)), CHAFA_PIXEL_RGBA8_UNASSOCIATED, false},
+		{"CMYK", cmyk, CHAFA_PIXEL_RGBA8_PREMULTIPLIED, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := drawNRGBA(tt.img)
+
+			pixels, pixelType, width, height, rowstride := imagePixels(tt.img)
+			if pixelType != tt.pixelType {
+				t.Fatalf("pixel type %d, want %d", pixelType, tt.pixelType)
+			}
+			if int(width) != want.Rect.Dx() || int(height) != want.Rect.Dy() {
+				t.Fatalf("size %dx%d, want %dx%d", width, height, want.Rect.Dx(), want.Rect.Dy())
+			}
+			if got := pixelsToNRGBA(t, pixels, pixelType, width, height, rowstride); !equalNRGBA(got, want) {
+				t.Errorf("pixels differ from draw.Draw\ngot:  %v\nwant: %v", got.Pix, want.Pix)
+			}
+
+			if shares := sharesPixels(tt.img, pixels); shares != tt.zeroCopy {
+				t.Errorf("imagePixels shares memory with the image: %v, want %v", shares, tt.zeroCopy)
+			}
+
+			pixels, pixelType, width, height, rowstride = imagePixelsCopy(tt.img)
+			if sharesPixels(tt.img, pixels) {
+				t.Error("imagePixelsCopy shares memory with the image")
+			}
+			if got := pixelsToNRGBA(t, pixels, pixelType, width, height, rowstride); !equalNRGBA(got, want) {
+				t.Errorf("copied pixels differ from draw.Draw\ngot:  %v\nwant: %v", got.Pix, want.Pix)
+			}
+		})
+	}
+}
+
+func equalNRGBA(a, b *image.NRGBA) bool {
+	return a.Rect == b.Rect && string(a.Pix) == string(b.Pix)
+}
+
+// sharesPixels reports whether pixels points into the pixel buffer of img.
+func sharesPixels(img image.Image, pixels []uint8) bool {
+	var pix []uint8
+	switch img := img.(type) {
+	case *image.RGBA:
+		pix = img.Pix
+	case *image.NRGBA:
+		pix = img.Pix
+	default:
+		return false
+	}
+
+	for i := range pix {
+		if &pix[i] == &pixels[0] {
+			return true
+		}
+	}
+	return false
+}
@@ -37,6 +37,16 @@ var (
 	PlacementSetVAlign func(placement *Placement, align Align)
 )
 
+// Creates a new [Placement] like [PlacementNew], but returns an error if the
+// loaded libchafa does not support placements (it needs 1.14 or newer).
+func PlacementNewGo(image *Image, id int32) (*Placement, error) {
+	if err := checkFunction("chafa_placement_new"); err != nil {
+		return nil, err
+	}
+
+	return PlacementNew(image, id), nil
+}
+
 type Placement struct {
 	Refs int32
 
@@ -0,0 +1,261 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Player plays an [Animation] on a terminal, drawing every frame over the
+// previous one, the way the chafa CLI does.
+//
+// Frames are composited on a single image as they are played, and rendered
+// with a [Renderer], which reuses one canvas for all of them since they share
+// a size. Between frames the cursor is moved back to
+// the top left corner of the image using sequences from the renderer's
+// [TermInfo], or fallback sequences if it has none.
+//
+// Pause, Resume and Seek may be called from other goroutines while Play runs.
+// A Player should be closed with [Player.Close] when no longer needed.
+type Player struct {
+	w        io.Writer
+	anim     *Animation
+	renderer *Renderer
+	termInfo *TermInfo
+
+	// Only used by Play
+	compositor *compositor
+
+	mu      sync.Mutex
+	frame   int
+	paused  bool
+	seekTo  int
+	seeking bool
+	changed chan struct{}
+}
+
+// NewPlayer creates a [Player] that writes anim to w, rendered with renderer.
+// The renderer's [TermInfo], if any, is used for cursor movement too.
+func NewPlayer(w io.Writer, anim *Animation, renderer *Renderer) (*Player, error) {
+	if len(anim.Frames) == 0 {
+		return nil, errors.New("chafa: animation has no frames")
+	}
+
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	p := &Player{
+		w:          w,
+		anim:       anim,
+		renderer:   renderer,
+		termInfo:   cursorTermInfo(renderer),
+		compositor: newCompositor(anim),
+		changed:    make(chan struct{}, 1),
+	}
+	runtime.SetFinalizer(p, (*Player).free)
+
+	return p, nil
+}
+
+// Close releases the [TermInfo] held by p. It must not be called while Play
+// runs, and p must not be played afterwards. It is safe to call Close more
+// than once.
+func (p *Player) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.free()
+	runtime.SetFinalizer(p, nil)
+
+	return nil
+}
+
+func (p *Player) free() {
+	if p.termInfo != nil {
+		TermInfoUnref(p.termInfo)
+		p.termInfo = nil
+	}
+}
+
+// Play plays the animation until it has looped [Animation.Loops] times or ctx
+// is done. The cursor is left on the line below the image.
+func (p *Player) Play(ctx context.Context) error {
+	loops := 0
+	drawn := false
+
+	for {
+		p.mu.Lock()
+		index := p.frame
+		p.mu.Unlock()
+
+		if err := p.draw(ctx, index, drawn); err != nil {
+			if drawn {
+				p.finish()
+			}
+			return err
+		}
+		drawn = true
+
+		advance, err := p.wait(ctx, p.anim.Frames[index].Delay)
+		if err != nil {
+			p.finish()
+			return err
+		}
+		if !advance {
+			// Seeked; draw the new frame right away
+			continue
+		}
+
+		p.mu.Lock()
+		p.frame++
+		if p.frame == len(p.anim.Frames) {
+			p.frame = 0
+			loops++
+		}
+		p.mu.Unlock()
+
+		if len(p.anim.Frames) == 1 || (p.anim.Loops > 0 && loops >= p.anim.Loops) {
+			return p.finish()
+		}
+	}
+}
+
+// Pause stops the animation on the current frame.
+func (p *Player) Pause() {
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+
+	p.notify()
+}
+
+// Resume continues a paused animation, starting with the full delay of the
+// current frame.
+func (p *Player) Resume() {
+	p.mu.Lock()
+	p.paused = false
+	p.mu.Unlock()
+
+	p.notify()
+}
+
+// Seek shows frame next, wrapping around if it is out of range. A paused
+// animation stays paused on the new frame.
+func (p *Player) Seek(frame int) {
+	n := len(p.anim.Frames)
+	frame = ((frame % n) + n) % n
+
+	p.mu.Lock()
+	p.seekTo, p.seeking = frame, true
+	p.mu.Unlock()
+
+	p.notify()
+}
+
+// Frame returns the index of the frame currently shown.
+func (p *Player) Frame() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.frame
+}
+
+func (p *Player) notify() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// draw renders frame index and writes it, first moving the cursor back over
+// the previous frame if there is one.
+func (p *Player) draw(ctx context.Context, index int, home bool) error {
+	img, err := p.compositor.frame(index)
+	if err != nil {
+		return err
+	}
+
+	out, err := p.renderer.RenderContext(ctx, img)
+	if err != nil {
+		return err
+	}
+
+	if home {
+		out = cursorHome(p.termInfo, p.renderer) + out
+	}
+
+	_, err = io.WriteString(p.w, out)
+	return err
+}
+
+// wait waits for delay to pass, or for the player to be changed. It reports
+// whether to go on to the next frame, rather than the one seeked to.
+func (p *Player) wait(ctx context.Context, delay time.Duration) (bool, error) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		p.mu.Lock()
+		paused := p.paused
+		if p.seeking {
+			p.frame, p.seeking = p.seekTo, false
+			p.mu.Unlock()
+			return false, nil
+		}
+		p.mu.Unlock()
+
+		var expired <-chan time.Time
+		if !paused {
+			expired = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-expired:
+			return true, nil
+		case <-p.changed:
+			if paused {
+				timer.Reset(delay)
+			}
+		}
+	}
+}
+
+// finish moves the cursor below the image.
+func (p *Player) finish() error {
+	_, err := io.WriteString(p.w, "\n")
+	return err
+}
+
+// cursorTermInfo returns a reference to the [TermInfo] used to move the
+// cursor over output from renderer: its own, or the fallback one.
+func cursorTermInfo(renderer *Renderer) *TermInfo {
+	if termInfo := renderer.opts.termInfo; termInfo != nil {
+		TermInfoRef(termInfo)
+		return termInfo
+	}
+
+	return TermDbGetFallbackInfo(TermDbGetDefault())
+}
+
+// cursorHome returns the sequence that moves the cursor from the end of the
+// last output from renderer back to its top left corner.
+func cursorHome(termInfo *TermInfo, renderer *Renderer) string {
+	renderer.mu.Lock()
+	rows := renderer.height
+	renderer.mu.Unlock()
+
+	seq := "\r"
+	if rows > 1 {
+		if up, err := formatSeq(termInfo, CHAFA_TERM_SEQ_CURSOR_UP, uint32(rows-1)); err == nil {
+			seq += up
+		}
+	}
+
+	return seq
+}
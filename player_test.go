@@ -0,0 +1,65 @@
+package chafa
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	"testing"
+	"time"
+)
+
+func TestPlayerLoops(t *testing.T) {
+	requireLibrary(t)
+
+	frame := func(phase int) AnimationFrame {
+		return AnimationFrame{
+			Bounds: image.Rect(0, 0, 16, 16),
+			Delay:  20 * time.Millisecond,
+			op:     draw.Src,
+			image:  testImage(16, 16, phase),
+		}
+	}
+	anim := &Animation{
+		Frames: []AnimationFrame{frame(0), frame(64), frame(128)},
+		Width:  16,
+		Height: 16,
+		Loops:  2,
+	}
+
+	renderer, err := NewRenderer(WithGeometry(8, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer renderer.Close()
+
+	var out bytes.Buffer
+	player, err := NewPlayer(&out, anim, renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+
+	start := time.Now()
+	if err := player.Play(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 6*20*time.Millisecond {
+		t.Errorf("played two loops of three 20ms frames in %v", elapsed)
+	}
+
+	// Every frame but the first starts by going back to the top left corner
+	if n := bytes.Count(out.Bytes(), []byte("\r\x1b[")); n != 5 {
+		t.Errorf("cursor moved back %d times, want 5", n)
+	}
+	if player.Frame() != 0 {
+		t.Errorf("ended on frame %d, want 0", player.Frame())
+	}
+
+	if err := player.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if player.termInfo != nil {
+		t.Error("Close kept the TermInfo")
+	}
+}
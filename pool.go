@@ -0,0 +1,312 @@
+package chafa
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrPoolClosed is returned by [CanvasPool.Get] after [CanvasPool.Close].
+	ErrPoolClosed = errors.New("chafa: canvas pool is closed")
+
+	// ErrCanvasTooLarge is returned by [CanvasPool.Get] when a single canvas
+	// would exceed the pool's memory limit.
+	ErrCanvasTooLarge = errors.New("chafa: canvas exceeds the pool's memory limit")
+)
+
+// CanvasSpec describes the canvases handed out by a [CanvasPool]. Canvases
+// are only reused for requests with an identical spec.
+type CanvasSpec struct {
+	// Canvas size in character cells.
+	Width, Height int32
+
+	// Size of a character cell in pixels. Zero leaves libchafa's default.
+	CellWidth, CellHeight int32
+
+	CanvasMode  CanvasMode
+	PixelMode   PixelMode
+	DitherMode  DitherMode
+	Passthrough Passthrough
+
+	// Work/quality tradeoff factor, from 0.0 to 1.0. Zero leaves libchafa's default.
+	WorkFactor float32
+
+	// Symbol selectors, as understood by [SymbolMapApplySelectors]. Empty
+	// means "all".
+	Symbols string
+}
+
+// CanvasPool hands out canvases to goroutines and reuses them, saving the
+// cost of [CanvasNew] for every image. A canvas must only be used by the
+// goroutine holding it, until it is returned with [CanvasPool.Put].
+//
+// The pool keeps the estimated memory used by all of its canvases, whether
+// idle or in use, under a limit. When a new canvas would exceed it, idle
+// canvases are freed, least recently used first, and if that is not enough
+// [CanvasPool.Get] waits for canvases to be returned.
+//
+// A CanvasPool is safe for use by multiple goroutines.
+type CanvasPool struct {
+	maxBytes int64
+
+	mu       sync.Mutex
+	used     int64
+	idle     map[CanvasSpec][]*poolEntry
+	lru      *list.List // Idle entries, most recently returned first
+	inUse    map[*Canvas]*poolEntry
+	released chan struct{}
+	closed   bool
+}
+
+type poolEntry struct {
+	spec   CanvasSpec
+	canvas *Canvas
+	size   int64
+	elem   *list.Element
+}
+
+// NewCanvasPool creates a [CanvasPool] whose canvases use at most maxBytes of
+// memory, as estimated from their geometry. A maxBytes of zero or less means
+// no limit.
+func NewCanvasPool(maxBytes int64) *CanvasPool {
+	return &CanvasPool{
+		maxBytes: maxBytes,
+		idle:     make(map[CanvasSpec][]*poolEntry),
+		lru:      list.New(),
+		inUse:    make(map[*Canvas]*poolEntry),
+		released: make(chan struct{}),
+	}
+}
+
+// Get returns a canvas matching spec, reusing an idle one if possible. It
+// blocks while the pool is at its memory limit, until a canvas is returned or
+// ctx is done. The canvas must be given back with [CanvasPool.Put].
+func (p *CanvasPool) Get(ctx context.Context, spec CanvasSpec) (*Canvas, error) {
+	if spec.Width <= 0 || spec.Height <= 0 {
+		return nil, fmt.Errorf("chafa: invalid geometry %dx%d", spec.Width, spec.Height)
+	}
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	size := estimateCanvasBytes(spec)
+	if p.maxBytes > 0 && size > p.maxBytes {
+		return nil, ErrCanvasTooLarge
+	}
+
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+
+		if entries := p.idle[spec]; len(entries) > 0 {
+			entry := entries[len(entries)-1]
+			p.removeIdleLocked(entry)
+			p.inUse[entry.canvas] = entry
+			p.mu.Unlock()
+			return entry.canvas, nil
+		}
+
+		if p.maxBytes <= 0 || p.used+size <= p.maxBytes {
+			break
+		}
+
+		if back := p.lru.Back(); back != nil {
+			entry := back.Value.(*poolEntry)
+			p.removeIdleLocked(entry)
+			p.used -= entry.size
+			CanvasUnRef(entry.canvas)
+			continue
+		}
+
+		released := p.released
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-released:
+		}
+
+		p.mu.Lock()
+	}
+
+	// Reserve the memory, and create the canvas without holding the lock
+	p.used += size
+	p.mu.Unlock()
+
+	canvas, err := newSpecCanvas(spec)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		p.used -= size
+		p.notifyLocked()
+		return nil, err
+	}
+	p.inUse[canvas] = &poolEntry{spec: spec, canvas: canvas, size: size}
+
+	return canvas, nil
+}
+
+// Put returns a canvas obtained from [CanvasPool.Get] to the pool. Its
+// contents are cleared, so they can never leak into the next user's output.
+// Canvases that did not come from the pool are ignored.
+func (p *CanvasPool) Put(canvas *Canvas) {
+	p.mu.Lock()
+	entry, ok := p.inUse[canvas]
+	if ok {
+		delete(p.inUse, canvas)
+	}
+	closed := p.closed
+	p.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if !closed {
+		resetCanvas(canvas)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		p.used -= entry.size
+		CanvasUnRef(canvas)
+		return
+	}
+
+	p.idle[entry.spec] = append(p.idle[entry.spec], entry)
+	entry.elem = p.lru.PushFront(entry)
+	p.notifyLocked()
+}
+
+// Close frees the idle canvases and makes further calls to [CanvasPool.Get]
+// fail. Canvases still in use are freed when they are returned.
+func (p *CanvasPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	for p.lru.Len() > 0 {
+		entry := p.lru.Back().Value.(*poolEntry)
+		p.removeIdleLocked(entry)
+		p.used -= entry.size
+		CanvasUnRef(entry.canvas)
+	}
+	p.notifyLocked()
+
+	return nil
+}
+
+// removeIdleLocked takes entry off the idle lists.
+func (p *CanvasPool) removeIdleLocked(entry *poolEntry) {
+	p.lru.Remove(entry.elem)
+	entry.elem = nil
+
+	entries := p.idle[entry.spec]
+	for i, e := range entries {
+		if e == entry {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+	if len(entries) == 0 {
+		delete(p.idle, entry.spec)
+	} else {
+		p.idle[entry.spec] = entries
+	}
+}
+
+// notifyLocked wakes up every Get waiting for memory.
+func (p *CanvasPool) notifyLocked() {
+	close(p.released)
+	p.released = make(chan struct{})
+}
+
+// newSpecCanvas creates a canvas configured as described by spec.
+func newSpecCanvas(spec CanvasSpec) (*Canvas, error) {
+	symbols := spec.Symbols
+	if symbols == "" {
+		symbols = "all"
+	}
+
+	symbolMap := SymbolMapNew()
+	defer SymbolMapUnref(symbolMap)
+	if err := SymbolMapApplySelectors(symbolMap, symbols); err != nil {
+		return nil, err
+	}
+
+	config := CanvasConfigNew()
+	defer CanvasConfigUnref(config)
+
+	CanvasConfigSetGeometry(config, spec.Width, spec.Height)
+	CanvasConfigSetCanvasMode(config, spec.CanvasMode)
+	CanvasConfigSetPixelMode(config, spec.PixelMode)
+	CanvasConfigSetDitherMode(config, spec.DitherMode)
+	if spec.Passthrough != CHAFA_PASSTHROUGH_NONE {
+		CanvasConfigSetPassthrough(config, spec.Passthrough)
+	}
+	if spec.WorkFactor > 0 {
+		CanvasConfigSetWorkFactor(config, spec.WorkFactor)
+	}
+	if spec.CellWidth > 0 && spec.CellHeight > 0 {
+		CanvasConfigSetCellGeometry(config, spec.CellWidth, spec.CellHeight)
+	}
+	CanvasConfigSetSymbolMap(config, symbolMap)
+
+	// The canvas keeps its own copy of the config
+	return CanvasNew(config), nil
+}
+
+// transparentPixel is drawn over a canvas to clear it.
+var transparentPixel = []uint8{0, 0, 0, 0}
+
+// resetCanvas clears the contents of canvas.
+func resetCanvas(canvas *Canvas) {
+	CanvasDrawAllPixels(canvas, CHAFA_PIXEL_RGBA8_UNASSOCIATED, transparentPixel, 1, 1, 4)
+}
+
+// Sizes used to estimate the memory held by a canvas.
+const (
+	canvasCellBytes  = 12 // ChafaCanvasCell
+	canvasPixelBytes = 4  // ChafaPixel
+	symbolCellPixels = 8  // Symbol cells are sampled at 8x8 pixels
+
+	defaultCellWidth, defaultCellHeight = 10, 20
+)
+
+// estimateCanvasBytes estimates the memory used by a canvas made from spec:
+// its cells, plus its pixel buffer.
+func estimateCanvasBytes(spec CanvasSpec) int64 {
+	cells := int64(spec.Width) * int64(spec.Height)
+	cellWidth, cellHeight := cellPixels(spec.PixelMode, spec.CellWidth, spec.CellHeight)
+
+	return cells*canvasCellBytes + cells*cellWidth*cellHeight*canvasPixelBytes
+}
+
+// cellPixels returns the size of the area of the canvas' pixel buffer that
+// backs one cell: 8x8 pixels in symbols mode, and the cell geometry in the
+// pixel modes.
+func cellPixels(pixelMode PixelMode, cellWidth, cellHeight int32) (int64, int64) {
+	if pixelMode == CHAFA_PIXEL_MODE_SYMBOLS {
+		return symbolCellPixels, symbolCellPixels
+	}
+	if cellWidth > 0 && cellHeight > 0 {
+		return int64(cellWidth), int64(cellHeight)
+	}
+
+	return defaultCellWidth, defaultCellHeight
+}
@@ -0,0 +1,105 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestCanvasPoolStress runs concurrent Get, Put and Close calls against a
+// pool with a memory limit, and checks that the limit always holds. Run it
+// with -race.
+func TestCanvasPoolStress(t *testing.T) {
+	requireLibrary(t)
+
+	specs := []CanvasSpec{
+		{Width: 20, Height: 10, CanvasMode: CHAFA_CANVAS_MODE_TRUECOLOR},
+		{Width: 40, Height: 12, CanvasMode: CHAFA_CANVAS_MODE_INDEXED_256},
+		{Width: 30, Height: 30, CanvasMode: CHAFA_CANVAS_MODE_TRUECOLOR, Symbols: "block"},
+	}
+
+	// Room for about two of the largest canvases at once
+	maxBytes := 2 * estimateCanvasBytes(specs[2])
+	pool := NewCanvasPool(maxBytes)
+
+	checkLimit := func() {
+		pool.mu.Lock()
+		used := pool.used
+		pool.mu.Unlock()
+
+		if used > maxBytes {
+			t.Errorf("pool uses %d bytes, more than its limit of %d", used, maxBytes)
+		}
+	}
+
+	img := testImage(32, 32, 0)
+
+	const (
+		workers    = 8
+		iterations = 200
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				canvas, err := pool.Get(ctx, specs[(i+j)%len(specs)])
+				cancel()
+
+				switch {
+				case errors.Is(err, ErrPoolClosed):
+					return
+				case errors.Is(err, context.DeadlineExceeded):
+					continue
+				case err != nil:
+					t.Error(err)
+					return
+				}
+
+				checkLimit()
+				CanvasDrawImage(canvas, img)
+				pool.Put(canvas)
+			}
+		}()
+	}
+
+	// Close the pool while the workers are busy
+	time.Sleep(20 * time.Millisecond)
+	checkLimit()
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if _, err := pool.Get(context.Background(), specs[0]); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Get after Close returned %v, want ErrPoolClosed", err)
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.used != 0 || len(pool.inUse) != 0 || pool.lru.Len() != 0 {
+		t.Errorf("pool still holds %d bytes, %d canvases in use and %d idle after Close",
+			pool.used, len(pool.inUse), pool.lru.Len())
+	}
+}
+
+// TestCanvasPoolTooLarge checks that a canvas larger than the limit is
+// refused instead of waiting forever.
+func TestCanvasPoolTooLarge(t *testing.T) {
+	requireLibrary(t)
+
+	spec := CanvasSpec{Width: 80, Height: 24}
+	pool := NewCanvasPool(estimateCanvasBytes(spec) - 1)
+	defer pool.Close()
+
+	if _, err := pool.Get(context.Background(), spec); !errors.Is(err, ErrCanvasTooLarge) {
+		t.Errorf("Get returned %v, want ErrCanvasTooLarge", err)
+	}
+}
@@ -0,0 +1,343 @@
+package chafa
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrNoResponse is returned by [Probe] when the terminal did not answer any
+// of its queries in time.
+var ErrNoResponse = errors.New("chafa: no response from terminal")
+
+// ProbeResult holds what [Probe] found out about a terminal. Values that the
+// terminal did not report are zero, or -1 for colors.
+type ProbeResult struct {
+	// The terminal's capabilities, as detected from the environment and
+	// refined with the responses. The caller should unref it when done.
+	TermInfo *TermInfo
+
+	// Parameters of the primary device attributes (DA1) response, e.g.
+	// [62 4 22]. Parameter 4 means the terminal supports sixels.
+	DeviceAttributes []uint32
+
+	// Whether the terminal supports sixel and Kitty graphics.
+	Sixel, Kitty bool
+
+	// Size of the text area in character cells and in pixels.
+	Width, Height     int
+	WidthPx, HeightPx int
+
+	// Size of a character cell in pixels.
+	CellWidth, CellHeight int
+
+	// Default foreground and background colors, packed as 0x00RRGGBB.
+	DefaultFg, DefaultBg int32
+}
+
+// Query for Kitty graphics support, a 1x1 RGB image that is only checked.
+const kittyQuery = "\x1b_Gi=31,s=1,v=1,a=q,t=d,f=24;AAAA\x1b\\"
+
+// Probe asks the terminal tty about its capabilities by writing queries to it
+// and parsing the responses. tty must be open for reading and writing, e.g.
+// /dev/tty, and is put in raw mode for the duration of the probe. On Windows
+// the queries go through the console's CONIN$ and CONOUT$ handles instead, so
+// tty may be any console handle, such as os.Stdout.
+//
+// The queries are for the primary device attributes, the cell and text area
+// size, the default colors and Kitty graphics support. Their responses
+// refine the [TermInfo] detected from the environment: sixel and Kitty
+// sequences that it lacks are added if the terminal turns out to support
+// them.
+//
+// Probe waits at most timeout for the responses. Terminals answer the
+// device attributes query last, so that usually ends the probe early.
+func Probe(tty *os.File, timeout time.Duration) (*ProbeResult, error) {
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	termInfo := TermDbDetect(TermDbGetDefault(), os.Environ())
+	fallback := TermDbGetFallbackInfo(TermDbGetDefault())
+	defer TermInfoUnref(fallback)
+
+	p, err := queryTerminal(tty, probeQueries(termInfo, fallback), fallback, timeout)
+	if err != nil {
+		TermInfoUnref(termInfo)
+		return nil, err
+	}
+
+	result := p.result
+	result.TermInfo = termInfo
+	refineTermInfo(&result)
+
+	return &result, nil
+}
+
+// queryTerminal writes queries to tty in raw mode, and parses the responses
+// with sequences from termInfo until the device attributes response arrives
+// or timeout passes.
+func queryTerminal(tty *os.File, queries string, termInfo *TermInfo, timeout time.Duration) (*probeParser, error) {
+	raw, err := openRaw(tty)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Close()
+
+	if _, err := io.WriteString(raw, queries); err != nil {
+		return nil, err
+	}
+
+	p := &probeParser{
+		termInfo: termInfo,
+		result:   ProbeResult{DefaultFg: -1, DefaultBg: -1},
+	}
+
+	buf := make([]byte, 1024)
+	deadline := time.Now().Add(timeout)
+	for !p.done && time.Now().Before(deadline) {
+		n, err := raw.read(buf)
+		if err != nil {
+			return nil, err
+		}
+		p.feed(buf[:n])
+	}
+
+	if !p.responded {
+		return nil, ErrNoResponse
+	}
+
+	return p, nil
+}
+
+// probeQueries returns the queries sent by [Probe], using sequences from
+// termInfo or, where it lacks them, fallback. Device attributes are asked for
+// last, as every terminal answers that.
+func probeQueries(termInfo, fallback *TermInfo) string {
+	var buf []byte
+
+	buf = append(buf, kittyQuery...)
+	for _, seq := range []TermSeq{
+		CHAFA_TERM_SEQ_QUERY_CELL_SIZE_PX,
+		CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_PX,
+		CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_CELLS,
+		CHAFA_TERM_SEQ_QUERY_DEFAULT_FG,
+		CHAFA_TERM_SEQ_QUERY_DEFAULT_BG,
+		CHAFA_TERM_SEQ_QUERY_PRIMARY_DEVICE_ATTRIBUTES,
+	} {
+		s, err := formatSeq(termInfo, seq)
+		if err != nil {
+			s, _ = formatSeq(fallback, seq)
+		}
+		buf = append(buf, s...)
+	}
+
+	return string(buf)
+}
+
+// Terminals whose [TermInfo] sequences are borrowed when a probed terminal
+// turns out to support a graphics protocol its own TermInfo lacks.
+var (
+	sixelReferenceEnv = []string{"TERM=mlterm"}
+	kittyReferenceEnv = []string{"TERM=xterm-kitty"}
+)
+
+// refineTermInfo adds the graphics sequences result shows to be supported
+// to result.TermInfo.
+func refineTermInfo(result *ProbeResult) {
+	supplement := func(have TermSeq, env []string) {
+		if TermInfoHaveSeq(result.TermInfo, have) {
+			return
+		}
+		reference := TermDbDetect(TermDbGetDefault(), env)
+		TermInfoSupplement(result.TermInfo, reference)
+		TermInfoUnref(reference)
+	}
+
+	if result.Sixel {
+		supplement(CHAFA_TERM_SEQ_BEGIN_SIXELS, sixelReferenceEnv)
+	}
+	if result.Kitty {
+		supplement(CHAFA_TERM_SEQ_BEGIN_KITTY_IMMEDIATE_IMAGE_V1, kittyReferenceEnv)
+	}
+}
+
+// probeParser parses the responses to the queries sent by [Probe].
+type probeParser struct {
+	termInfo *TermInfo
+	pending  []byte
+	result   ProbeResult
+
+	// Whether any response was seen, and whether the final one was.
+	responded, done bool
+}
+
+// feed parses the responses in data, keeping incomplete ones for later.
+func (p *probeParser) feed(data []byte) {
+	p.pending = append(p.pending, data...)
+
+	for len(p.pending) > 0 {
+		n, ok := p.parse(p.pending)
+		if !ok {
+			// Incomplete; wait for more input
+			return
+		}
+		p.pending = p.pending[n:]
+	}
+}
+
+// parse parses one response at the start of data, returning how many bytes
+// it used. Unrecognized bytes are skipped one at a time. ok is false if data
+// could be the start of a response.
+func (p *probeParser) parse(data []byte) (n int, ok bool) {
+	if data[0] != '\x1b' {
+		return 1, true
+	}
+
+	again := false
+
+	args, n, result := parseReply(p.termInfo, CHAFA_TERM_SEQ_PRIMARY_DEVICE_ATTRIBUTES, data)
+	switch result {
+	case CHAFA_PARSE_SUCCESS:
+		p.result.DeviceAttributes = args
+		p.result.Sixel = slices.Contains(args, 4)
+		p.responded, p.done = true, true
+		return n, true
+	case CHAFA_PARSE_AGAIN:
+		again = true
+	}
+
+	for _, seq := range []TermSeq{
+		CHAFA_TERM_SEQ_CELL_SIZE_PX,
+		CHAFA_TERM_SEQ_TEXT_AREA_SIZE_PX,
+		CHAFA_TERM_SEQ_TEXT_AREA_SIZE_CELLS,
+	} {
+		args, n, result := parseReply(p.termInfo, seq, data)
+		switch result {
+		case CHAFA_PARSE_SUCCESS:
+			// The height comes first, as in xterm's responses
+			height, width := int(args[0]), int(args[1])
+			switch seq {
+			case CHAFA_TERM_SEQ_CELL_SIZE_PX:
+				p.result.CellWidth, p.result.CellHeight = width, height
+			case CHAFA_TERM_SEQ_TEXT_AREA_SIZE_PX:
+				p.result.WidthPx, p.result.HeightPx = width, height
+			case CHAFA_TERM_SEQ_TEXT_AREA_SIZE_CELLS:
+				p.result.Width, p.result.Height = width, height
+			}
+			p.responded = true
+			return n, true
+		case CHAFA_PARSE_AGAIN:
+			again = true
+		}
+	}
+
+	// OSC 10/11 color and APC Kitty responses, which libchafa can't parse
+	if len(data) >= 2 && (data[1] == ']' || data[1] == '_') {
+		body, n, complete := stringSequence(data)
+		if !complete {
+			return 0, false
+		}
+		if body != nil {
+			p.parseStringResponse(body)
+			p.responded = true
+		}
+		return n, true
+	}
+
+	if again {
+		return 0, false
+	}
+	return 1, true
+}
+
+// parseStringResponse parses the body of an OSC or APC response.
+func (p *probeParser) parseStringResponse(body []byte) {
+	switch {
+	case bytes.HasPrefix(body, []byte("]10;")):
+		p.result.DefaultFg = parseXColor(body[4:])
+	case bytes.HasPrefix(body, []byte("]11;")):
+		p.result.DefaultBg = parseXColor(body[4:])
+	case bytes.HasPrefix(body, []byte("_Gi=31;")):
+		p.result.Kitty = bytes.Equal(body[7:], []byte("OK"))
+	}
+}
+
+// parseReply parses a response of type seq at the start of data, returning
+// its arguments and length.
+func parseReply(termInfo *TermInfo, seq TermSeq, data []byte) ([]uint32, int, ParseResult) {
+	if !TermInfoHaveSeq(termInfo, seq) {
+		return nil, 0, CHAFA_PARSE_FAILURE
+	}
+
+	// libchafa advances the string's data pointer, so give it a copy
+	input := []string{string(data)}
+	inputLen := int32(len(data))
+
+	var args [CHAFA_TERM_SEQ_ARGS_MAX]uint32
+	var nArgs int32
+	result := TermInfoParseSeqVarargs(termInfo, seq, input, &inputLen, &args[0], &nArgs)
+	if result != CHAFA_PARSE_SUCCESS {
+		return nil, 0, result
+	}
+
+	return slices.Clone(args[:nArgs]), len(data) - int(inputLen), result
+}
+
+// stringSequence splits off an OSC or APC sequence, terminated by ST (ESC \)
+// or BEL, from the start of data. It returns the sequence without its ESC and
+// terminator, and its full length. Any other ESC starts a new sequence, so
+// the unterminated one before it is returned as a nil body.
+func stringSequence(data []byte) (body []byte, n int, complete bool) {
+	for i := 2; i < len(data); i++ {
+		switch {
+		case data[i] == '\a':
+			return data[1:i], i + 1, true
+		case data[i] == '\x1b':
+			if i+1 == len(data) {
+				return nil, 0, false
+			}
+			if data[i+1] == '\\' {
+				return data[1:i], i + 2, true
+			}
+			return nil, i, true
+		}
+	}
+	return nil, 0, false
+}
+
+// parseXColor parses an X11 color specification as used in OSC color
+// responses, e.g. "rgb:ffff/8080/0000", into a packed 0x00RRGGBB color. It
+// returns -1 if spec is not understood.
+func parseXColor(spec []byte) int32 {
+	rgb, ok := bytes.CutPrefix(spec, []byte("rgb:"))
+	if !ok {
+		return -1
+	}
+
+	parts := bytes.Split(rgb, []byte("/"))
+	if len(parts) != 3 {
+		return -1
+	}
+
+	var color int32
+	for _, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return -1
+		}
+		v, err := strconv.ParseUint(string(part), 16, 16)
+		if err != nil {
+			return -1
+		}
+
+		// Scale from 1-4 hex digits to 8 bits
+		maxValue := uint64(1)<<(4*len(part)) - 1
+		color = color<<8 | int32(v*255/maxValue)
+	}
+
+	return color
+}
@@ -0,0 +1,162 @@
+package chafa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a pseudo-terminal pair, returning the master side, played by
+// the test as the terminal, and the slave side, which is given to the code
+// under test.
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("cannot open a pseudo-terminal: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatal(err)
+	}
+	n, err := unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { slave.Close() })
+
+	return master, slave
+}
+
+// fakeTerminal plays a terminal on the master side of a pseudo-terminal. It
+// reads queries until the device attributes one, then answers each query it
+// recognizes with the response in replies, in pieces to test reassembly.
+func fakeTerminal(master *os.File, replies map[string]string) <-chan []byte {
+	queries := make(chan []byte, 1)
+
+	go func() {
+		var received []byte
+		buf := make([]byte, 256)
+		for !bytes.HasSuffix(received, []byte("\x1b[0c")) {
+			n, err := master.Read(buf)
+			if err != nil {
+				queries <- received
+				return
+			}
+			received = append(received, buf[:n]...)
+		}
+		queries <- received
+
+		var response []byte
+		for _, query := range probeTestQueries {
+			if reply, ok := replies[query]; ok && bytes.Contains(received, []byte(query)) {
+				response = append(response, reply...)
+			}
+		}
+		for len(response) > 0 {
+			n := min(len(response), 7)
+			master.Write(response[:n])
+			response = response[n:]
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	return queries
+}
+
+// The queries sent by Probe, in the order they are answered.
+var probeTestQueries = []string{
+	kittyQuery,
+	"\x1b[16t",
+	"\x1b[14t",
+	"\x1b[18t",
+	"\x1b]10;?\x1b\\",
+	"\x1b]11;?\x1b\\",
+	"\x1b[0c",
+}
+
+func TestProbe(t *testing.T) {
+	requireLibrary(t)
+	t.Setenv("TERM", "xterm-256color")
+	for _, key := range []string{"TERM_PROGRAM", "TMUX", "STY", "KITTY_WINDOW_ID", "COLORTERM", "VTE_VERSION"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	master, slave := openPTY(t)
+	queries := fakeTerminal(master, map[string]string{
+		kittyQuery:        "\x1b_Gi=31;OK\x1b\\",
+		"\x1b[16t":        "\x1b[6;20;10t",
+		"\x1b[14t":        "\x1b[4;480;800t",
+		"\x1b[18t":        "\x1b[8;24;80t",
+		"\x1b]10;?\x1b\\": "\x1b]10;rgb:ffff/8080/0000\x1b\\",
+		"\x1b]11;?\x1b\\": "\x1b]11;rgb:00/00/ff\a",
+		"\x1b[0c":         "\x1b[?62;4;22c",
+	})
+
+	result, err := Probe(slave, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer TermInfoUnref(result.TermInfo)
+
+	sent := <-queries
+	for _, query := range probeTestQueries {
+		if !bytes.Contains(sent, []byte(query)) {
+			t.Errorf("query %q was not sent, got %q", query, sent)
+		}
+	}
+
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"device attributes", fmt.Sprint(result.DeviceAttributes), "[62 4 22]"},
+		{"sixel", result.Sixel, true},
+		{"kitty", result.Kitty, true},
+		{"cell size", [2]int{result.CellWidth, result.CellHeight}, [2]int{10, 20}},
+		{"size in pixels", [2]int{result.WidthPx, result.HeightPx}, [2]int{800, 480}},
+		{"size in cells", [2]int{result.Width, result.Height}, [2]int{80, 24}},
+		{"default fg", result.DefaultFg, int32(0xff8000)},
+		{"default bg", result.DefaultBg, int32(0x0000ff)},
+		{"sixel sequences", TermInfoHaveSeq(result.TermInfo, CHAFA_TERM_SEQ_BEGIN_SIXELS), true},
+		{"kitty sequences", TermInfoHaveSeq(result.TermInfo, CHAFA_TERM_SEQ_BEGIN_KITTY_IMMEDIATE_IMAGE_V1), true},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestProbeNoResponse(t *testing.T) {
+	requireLibrary(t)
+
+	master, slave := openPTY(t)
+	fakeTerminal(master, nil)
+
+	const timeout = 300 * time.Millisecond
+	start := time.Now()
+	_, err := Probe(slave, timeout)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrNoResponse) {
+		t.Errorf("Probe returned %v, want ErrNoResponse", err)
+	}
+	if elapsed < timeout || elapsed > timeout+time.Second {
+		t.Errorf("Probe took %v, want about %v", elapsed, timeout)
+	}
+}
@@ -0,0 +1,53 @@
+package chafa
+
+import "testing"
+
+func TestStringSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		body     string
+		n        int
+		complete bool
+	}{
+		{"ST", "\x1b]10;rgb:ff/80/00\x1b\\rest", "]10;rgb:ff/80/00", 19, true},
+		{"BEL", "\x1b_Gi=31;OK\arest", "_Gi=31;OK", 11, true},
+		{"unterminated", "\x1b]10;rgb:ff/8", "", 0, false},
+		{"ESC at the end", "\x1b]10;rgb:ff/80/00\x1b", "", 0, false},
+		{"interrupted by CSI", "\x1b]10;rgb:ff\x1b[?62c", "", 11, true},
+		{"interrupted by OSC", "\x1b]10;rgb:ff\x1b]11;rgb:00/00/ff\a", "", 11, true},
+	}
+	for _, tt := range tests {
+		body, n, complete := stringSequence([]byte(tt.data))
+		if string(body) != tt.body || n != tt.n || complete != tt.complete {
+			t.Errorf("%s: got %q, %d, %v, want %q, %d, %v", tt.name, body, n, complete, tt.body, tt.n, tt.complete)
+		}
+	}
+}
+
+func TestProbeParserSkipsInterruptedString(t *testing.T) {
+	requireLibrary(t)
+
+	fallback := TermDbGetFallbackInfo(TermDbGetDefault())
+	defer TermInfoUnref(fallback)
+
+	p := &probeParser{
+		termInfo: fallback,
+		result:   ProbeResult{DefaultFg: -1, DefaultBg: -1},
+	}
+
+	// The foreground response is cut off by the background one, which must
+	// still be parsed, as must the device attributes after it
+	p.feed([]byte("\x1b]10;rgb:ffff/80"))
+	p.feed([]byte("\x1b]11;rgb:00/00/ff\x1b\\\x1b[?62;4c"))
+
+	if p.result.DefaultFg != -1 {
+		t.Errorf("DefaultFg = %#06x from an interrupted response, want -1", p.result.DefaultFg)
+	}
+	if p.result.DefaultBg != 0x0000ff {
+		t.Errorf("DefaultBg = %#06x, want 0x0000ff", p.result.DefaultBg)
+	}
+	if !p.done || !p.result.Sixel {
+		t.Errorf("device attributes were not parsed: done %v, sixel %v", p.done, p.result.Sixel)
+	}
+}
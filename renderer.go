@@ -0,0 +1,398 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrRendererClosed is returned when a [Renderer] is used after [Renderer.Close].
+var ErrRendererClosed = errors.New("chafa: renderer is closed")
+
+var errEmptyImage = errors.New("chafa: image is empty")
+
+// Renderer converts Go images to printable terminal output. It owns the
+// [CanvasConfig], [SymbolMap] and [Canvas] needed to do so, and releases them
+// when closed.
+//
+// A Renderer is safe for use by multiple goroutines, but renders are
+// serialized since they share a single canvas.
+type Renderer struct {
+	mu sync.Mutex
+
+	opts      rendererOptions
+	config    *CanvasConfig
+	symbolMap *SymbolMap
+	termInfo  *TermInfo
+
+	canvas        *Canvas
+	width, height int32
+
+	// Holds a token while a RenderContext worker exists, so that abandoned
+	// workers can't pile up.
+	worker chan struct{}
+
+	// Measured cost of drawing one source pixel, used with a time budget.
+	nsPerPixel float64
+}
+
+type rendererOptions struct {
+	width, height         int32
+	cellWidth, cellHeight int32
+	canvasMode            CanvasMode
+	pixelMode             PixelMode
+	ditherMode            DitherMode
+	passthrough           Passthrough
+	workFactor            float32
+	symbols               string
+	termInfo              *TermInfo
+	timeBudget            time.Duration
+}
+
+// RendererOption configures a [Renderer] created with [NewRenderer].
+type RendererOption func(*rendererOptions)
+
+// WithGeometry sets the maximum canvas size in character cells. The image
+// is fitted inside this box, preserving its aspect ratio. The default is 80x24.
+func WithGeometry(width, height int32) RendererOption {
+	return func(o *rendererOptions) {
+		o.width, o.height = width, height
+	}
+}
+
+// WithCellGeometry sets the size of a character cell in pixels. This is
+// used to derive the font ratio when fitting images, and by the pixel modes.
+func WithCellGeometry(cellWidth, cellHeight int32) RendererOption {
+	return func(o *rendererOptions) {
+		o.cellWidth, o.cellHeight = cellWidth, cellHeight
+	}
+}
+
+// WithCanvasMode sets the [CanvasMode]. The default is [CHAFA_CANVAS_MODE_TRUECOLOR].
+func WithCanvasMode(mode CanvasMode) RendererOption {
+	return func(o *rendererOptions) {
+		o.canvasMode = mode
+	}
+}
+
+// WithPixelMode sets the [PixelMode]. The default is [CHAFA_PIXEL_MODE_SYMBOLS].
+func WithPixelMode(mode PixelMode) RendererOption {
+	return func(o *rendererOptions) {
+		o.pixelMode = mode
+	}
+}
+
+// WithDitherMode sets the [DitherMode]. The default is [CHAFA_DITHER_MODE_NONE].
+func WithDitherMode(mode DitherMode) RendererOption {
+	return func(o *rendererOptions) {
+		o.ditherMode = mode
+	}
+}
+
+// WithPassthrough sets the [Passthrough] mode. The default is [CHAFA_PASSTHROUGH_NONE].
+func WithPassthrough(passthrough Passthrough) RendererOption {
+	return func(o *rendererOptions) {
+		o.passthrough = passthrough
+	}
+}
+
+// WithWorkFactor sets the work/quality tradeoff factor, from 0.0 to 1.0.
+func WithWorkFactor(workFactor float32) RendererOption {
+	return func(o *rendererOptions) {
+		o.workFactor = workFactor
+	}
+}
+
+// WithSymbols selects the symbols used in [CHAFA_PIXEL_MODE_SYMBOLS] with a
+// selector string, as understood by [SymbolMapApplySelectors]. The default is "all".
+func WithSymbols(selectors string) RendererOption {
+	return func(o *rendererOptions) {
+		o.symbols = selectors
+	}
+}
+
+// WithTermInfo sets the [TermInfo] used to build the output. The renderer
+// keeps its own reference. If unset, fallback control sequences are used.
+func WithTermInfo(termInfo *TermInfo) RendererOption {
+	return func(o *rendererOptions) {
+		o.termInfo = termInfo
+	}
+}
+
+// WithTimeBudget sets a target duration for drawing each image. Images that
+// are expected to take longer, based on how long earlier renders took per
+// pixel, are first downscaled in Go, but never below the resolution the
+// canvas samples at, so the output is rarely affected.
+//
+// This does not bound the time a render takes; combine it with a context
+// deadline and [Renderer.RenderContext] for that.
+func WithTimeBudget(budget time.Duration) RendererOption {
+	return func(o *rendererOptions) {
+		o.timeBudget = budget
+	}
+}
+
+// NewRenderer creates a [Renderer] configured with opts. The caller should
+// call [Renderer.Close] when done with it.
+func NewRenderer(opts ...RendererOption) (*Renderer, error) {
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	o := rendererOptions{
+		width:      80,
+		height:     24,
+		canvasMode: CHAFA_CANVAS_MODE_TRUECOLOR,
+		pixelMode:  CHAFA_PIXEL_MODE_SYMBOLS,
+		ditherMode: CHAFA_DITHER_MODE_NONE,
+		workFactor: 0.5,
+		symbols:    "all",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.width <= 0 || o.height <= 0 {
+		return nil, fmt.Errorf("chafa: invalid geometry %dx%d", o.width, o.height)
+	}
+
+	r := &Renderer{opts: o, worker: make(chan struct{}, 1)}
+
+	r.symbolMap = SymbolMapNew()
+	if err := SymbolMapApplySelectors(r.symbolMap, o.symbols); err != nil {
+		SymbolMapUnref(r.symbolMap)
+		return nil, err
+	}
+
+	r.config = CanvasConfigNew()
+	CanvasConfigSetCanvasMode(r.config, o.canvasMode)
+	CanvasConfigSetPixelMode(r.config, o.pixelMode)
+	CanvasConfigSetDitherMode(r.config, o.ditherMode)
+	if o.passthrough != CHAFA_PASSTHROUGH_NONE {
+		CanvasConfigSetPassthrough(r.config, o.passthrough)
+	}
+	CanvasConfigSetWorkFactor(r.config, o.workFactor)
+	CanvasConfigSetSymbolMap(r.config, r.symbolMap)
+	if o.cellWidth > 0 && o.cellHeight > 0 {
+		CanvasConfigSetCellGeometry(r.config, o.cellWidth, o.cellHeight)
+	}
+
+	if o.termInfo != nil {
+		TermInfoRef(o.termInfo)
+		r.termInfo = o.termInfo
+	}
+
+	runtime.SetFinalizer(r, (*Renderer).free)
+
+	return r, nil
+}
+
+// Render draws img and returns a string of terminal control sequences and
+// symbols representing it.
+func (r *Renderer) Render(img image.Image) (string, error) {
+	return r.RenderContext(context.Background(), img)
+}
+
+// RenderContext is like [Renderer.Render], but gives up when ctx is done,
+// returning ctx.Err().
+//
+// libchafa cannot be interrupted, so the work runs on a worker goroutine
+// which carries on after RenderContext returns, and later renders wait for
+// it to finish. A Renderer has at most one worker at a time, and waiting for
+// it can be cancelled too. The pixels of img are copied before the worker
+// starts, so img may be modified as soon as RenderContext returns.
+func (r *Renderer) RenderContext(ctx context.Context, img image.Image) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if ctx.Done() == nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		return r.renderLocked(img)
+	}
+
+	if img.Bounds().Empty() {
+		return "", errEmptyImage
+	}
+	pixels, pixelType, width, height, rowstride := imagePixelsCopy(img)
+
+	// Wait for the previous worker, which may be finishing abandoned work
+	select {
+	case r.worker <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	type result struct {
+		out string
+		err error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		defer func() { <-r.worker }()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		// Don't start on work nobody is waiting for anymore
+		if err := ctx.Err(); err != nil {
+			done <- result{err: err}
+			return
+		}
+
+		out, err := r.renderPixelsLocked(pixels, pixelType, width, height, rowstride)
+		done <- result{out, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case res := <-done:
+		return res.out, res.err
+	}
+}
+
+func (r *Renderer) renderLocked(img image.Image) (string, error) {
+	if r.config == nil {
+		return "", ErrRendererClosed
+	}
+
+	if img.Bounds().Empty() {
+		return "", errEmptyImage
+	}
+
+	pixels, pixelType, width, height, rowstride := imagePixels(img)
+
+	return r.renderPixelsLocked(pixels, pixelType, width, height, rowstride)
+}
+
+// renderPixelsLocked is like renderLocked, but takes raw pixel data.
+func (r *Renderer) renderPixelsLocked(
+	pixels []uint8,
+	pixelType PixelType,
+	width, height, rowstride int32,
+) (string, error) {
+	if r.config == nil {
+		return "", ErrRendererClosed
+	}
+
+	r.prepareCanvas(width, height)
+
+	if r.opts.timeBudget > 0 {
+		pixels, width, height, rowstride = r.fitTimeBudget(pixels, pixelType, width, height, rowstride)
+	}
+
+	start := time.Now()
+	CanvasDrawAllPixels(r.canvas, pixelType, pixels, width, height, rowstride)
+	r.recordDrawTime(time.Since(start), width, height)
+
+	return CanvasPrintString(r.canvas, r.termInfo), nil
+}
+
+// Cost per pixel assumed by [WithTimeBudget] until a render has been measured.
+const defaultNsPerPixel = 50
+
+// fitTimeBudget downscales the image if drawing it is expected to take longer
+// than the time budget.
+func (r *Renderer) fitTimeBudget(
+	pixels []uint8,
+	pixelType PixelType,
+	width, height, rowstride int32,
+) ([]uint8, int32, int32, int32) {
+	nsPerPixel := r.nsPerPixel
+	if nsPerPixel <= 0 {
+		nsPerPixel = defaultNsPerPixel
+	}
+
+	maxPixels := float64(r.opts.timeBudget.Nanoseconds()) / nsPerPixel
+
+	// Going below the canvas' own resolution would only lose detail
+	cellWidth, cellHeight := cellPixels(r.opts.pixelMode, r.opts.cellWidth, r.opts.cellHeight)
+	maxPixels = max(maxPixels, float64(int64(r.width)*cellWidth*int64(r.height)*cellHeight))
+
+	if float64(width)*float64(height) <= maxPixels {
+		return pixels, width, height, rowstride
+	}
+
+	scale := math.Sqrt(maxPixels / (float64(width) * float64(height)))
+	dstWidth := max(int32(float64(width)*scale), 1)
+	dstHeight := max(int32(float64(height)*scale), 1)
+
+	pixels, rowstride = downscalePixels(pixels, pixelType, width, height, rowstride, dstWidth, dstHeight)
+
+	return pixels, dstWidth, dstHeight, rowstride
+}
+
+// recordDrawTime updates the per-pixel cost estimate used by [WithTimeBudget].
+func (r *Renderer) recordDrawTime(elapsed time.Duration, width, height int32) {
+	nsPerPixel := float64(elapsed.Nanoseconds()) / (float64(width) * float64(height))
+	if r.nsPerPixel <= 0 {
+		r.nsPerPixel = nsPerPixel
+	} else {
+		r.nsPerPixel = 0.7*r.nsPerPixel + 0.3*nsPerPixel
+	}
+}
+
+// Close releases the C objects owned by the renderer. It is safe to call
+// Close more than once.
+func (r *Renderer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.free()
+	runtime.SetFinalizer(r, nil)
+
+	return nil
+}
+
+// prepareCanvas makes sure r.canvas fits an image of srcWidth x srcHeight
+// pixels, replacing it if the geometry changed.
+func (r *Renderer) prepareCanvas(srcWidth, srcHeight int32) {
+	fontRatio := float32(0.5)
+	if r.opts.cellWidth > 0 && r.opts.cellHeight > 0 {
+		fontRatio = float32(r.opts.cellWidth) / float32(r.opts.cellHeight)
+	}
+
+	width, height := r.opts.width, r.opts.height
+	CalcCanvasGeometry(srcWidth, srcHeight, &width, &height, fontRatio, false, false)
+	width, height = max(width, 1), max(height, 1)
+
+	if r.canvas != nil && width == r.width && height == r.height {
+		return
+	}
+
+	if r.canvas != nil {
+		CanvasUnRef(r.canvas)
+	}
+
+	CanvasConfigSetGeometry(r.config, width, height)
+	r.canvas = CanvasNew(r.config)
+	r.width, r.height = width, height
+}
+
+func (r *Renderer) free() {
+	if r.canvas != nil {
+		CanvasUnRef(r.canvas)
+		r.canvas = nil
+	}
+	if r.config != nil {
+		CanvasConfigUnref(r.config)
+		r.config = nil
+	}
+	if r.symbolMap != nil {
+		SymbolMapUnref(r.symbolMap)
+		r.symbolMap = nil
+	}
+	if r.termInfo != nil {
+		TermInfoUnref(r.termInfo)
+		r.termInfo = nil
+	}
+}
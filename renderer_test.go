@@ -0,0 +1,70 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRenderContextAbandoned cancels many renders of images that are
+// modified as soon as each call returns, and checks that the abandoned
+// workers don't pile up.
+func TestRenderContextAbandoned(t *testing.T) {
+	requireLibrary(t)
+
+	r, err := NewRenderer(WithGeometry(80, 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	baseline := runtime.NumGoroutine()
+
+	const callers = 16
+	var (
+		wg   sync.WaitGroup
+		peak int
+		mu   sync.Mutex
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			img := testImage(400, 300, i)
+			for j := 0; j < 4; j++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				_, err := r.RenderContext(ctx, img)
+				cancel()
+				if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+					t.Error(err)
+				}
+
+				// The caller owns img again
+				for k := range img.Pix {
+					img.Pix[k]++
+				}
+
+				mu.Lock()
+				peak = max(peak, runtime.NumGoroutine()-baseline)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every caller and the one worker, with room for the goroutines that
+	// run the contexts' cancel functions
+	if peak > 2*callers {
+		t.Errorf("%d goroutines above the baseline, want at most %d", peak, 2*callers)
+	}
+
+	// Once the worker is done, a render succeeds
+	out, err := r.RenderContext(context.Background(), testImage(40, 30, 0))
+	if err != nil || out == "" {
+		t.Errorf("render after cancellations returned %q, %v", out, err)
+	}
+}
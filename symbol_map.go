@@ -1,6 +1,9 @@
 package chafa
 
-import "unsafe"
+import (
+	"fmt"
+	"unsafe"
+)
 
 var (
 	// Creates a new [SymbolMap] representing a set of Unicode symbols.
@@ -45,7 +48,7 @@ var (
 	// removes dot and stipple symbols.
 	//
 	// If there is a parse error, none of the changes are applied.
-	SymbolMapApplySelectors func(symbolMap *SymbolMap, selectors string) bool
+	symbolMapApplySelectors func(symbolMap *SymbolMap, selectors string, err **GError) bool
 
 	// Queries whether a symbol map is allowed to use built-in glyphs for symbol
 	// selection. This can be turned off if you want to use your own glyphs
@@ -99,11 +102,42 @@ var (
 	)
 )
 
+// Parses a string consisting of symbol tags separated by [+-,] and applies
+// the pattern to symbolMap. If the string begins with + or -, it's
+// understood to be relative to the current set in symbolMap, otherwise the
+// map is cleared first.
+//
+// The symbol tags are string versions of [SymbolTags], i.e. [all, none,
+// space, solid, stipple, block, border, diagonal, dot, quad, half, hhalf,
+// vhalf, braille, technical, geometric, ascii, extra].
+//
+// Examples: "block,border" sets map to contain symbols matching either of
+// those tags. "+block,border-dot,stipple" adds block and border symbols then
+// removes dot and stipple symbols.
+//
+// If there is a parse error, none of the changes are applied and an [*Error]
+// describing it is returned.
+func SymbolMapApplySelectors(symbolMap *SymbolMap, selectors string) error {
+	if err := Init(); err != nil {
+		return err
+	}
+
+	var gerr *GError
+	if !symbolMapApplySelectors(symbolMap, selectors, &gerr) {
+		if err := gErrorToGo(gerr); err != nil {
+			return err
+		}
+		return fmt.Errorf("chafa: invalid symbol selectors %q", selectors)
+	}
+
+	return nil
+}
+
 type SymbolMap struct {
 	Refs int32
 
-	NeedRebuild      bool
-	UseBuiltinGlyphs bool
+	// C bit fields: need_rebuild is bit 0, use_builtin_glyphs is bit 1.
+	Flags uint32
 
 	Glyphs    unsafe.Pointer
 	Glyphs2   unsafe.Pointer // Wide glyphs with left/right bitmaps
@@ -112,12 +146,12 @@ type SymbolMap struct {
 	// /* Remaining fields are populated by chafa_symbol_map_prepare () */
 
 	// Narrow symbols
-	Symbols       []Symbol
+	Symbols       *Symbol
 	NSymbols      int32
 	PackedBitmaps *uint64
 
 	// Wide symbols
-	Symbols2       []Symbol2
+	Symbols2       *Symbol2
 	NSymbols2      int32
 	PackedBitmaps2 *uint64
 }
@@ -1,6 +1,9 @@
 package chafa
 
-import "unsafe"
+import (
+	"runtime"
+	"unsafe"
+)
 
 var (
 	// Creates a new, blank [TermDb].
@@ -42,12 +45,16 @@ func TermDbDetect(termDb *TermDb, envp []string) *TermInfo {
 		ptrs[i] = &cstr[0]
 	}
 
-	ptrBlock := make([]uintptr, len(ptrs))
+	// The array must be NULL-terminated
+	ptrBlock := make([]uintptr, len(ptrs)+1)
 	for i, p := range ptrs {
 		ptrBlock[i] = uintptr(unsafe.Pointer(p))
 	}
 
-	return termDbDetect(termDb, (**byte)(unsafe.Pointer(&ptrBlock[0])))
+	termInfo := termDbDetect(termDb, (**byte)(unsafe.Pointer(&ptrBlock[0])))
+	runtime.KeepAlive(allocated)
+
+	return termInfo
 }
 
 type TermDb struct {
@@ -0,0 +1,221 @@
+package chafa
+
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
+// TermMatch selects environments by their variables. Each key is the name of
+// an environment variable, and each value a pattern, as understood by
+// path.Match, that the variable must match. For instance
+//
+//	chafa.TermMatch{"TERM_PROGRAM": "webterm", "TERM": "xterm-*"}
+//
+// An empty TermMatch matches every environment.
+type TermMatch map[string]string
+
+func (m TermMatch) matches(env map[string]string) bool {
+	for key, pattern := range m {
+		value, ok := env[key]
+		if !ok {
+			return false
+		}
+		if matched, err := path.Match(pattern, value); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// MultiplexerRule reports whether the environment, read through getenv, is
+// inside a multiplexer that libchafa detects by itself, such as tmux or GNU
+// Screen. See [TermDbOverlay.SetMultiplexerRule].
+type MultiplexerRule func(getenv func(key string) string) bool
+
+// DefaultMultiplexerRule is the [MultiplexerRule] a [TermDbOverlay] starts
+// with. It matches when TMUX or STY is set, or TERM starts with "tmux" or
+// "screen", as it does in a shell started by tmux or GNU Screen.
+func DefaultMultiplexerRule(getenv func(key string) string) bool {
+	term := getenv("TERM")
+	return getenv("TMUX") != "" || getenv("STY") != "" ||
+		strings.HasPrefix(term, "tmux") || strings.HasPrefix(term, "screen")
+}
+
+type overlayEntry struct {
+	match    TermMatch
+	termInfo *TermInfo
+}
+
+// TermDbOverlay extends a [TermDb] with terminals registered from Go, such as
+// in-house terminal emulators or web-based frontends that libchafa does not
+// know about, selected by environment variables.
+//
+// A TermDbOverlay is safe for use by multiple goroutines.
+type TermDbOverlay struct {
+	termDb *TermDb
+
+	mu              sync.RWMutex
+	terminals       []overlayEntry
+	multiplexers    []overlayEntry
+	multiplexerRule MultiplexerRule
+	closed          bool
+}
+
+// NewTermDbOverlay creates a [TermDbOverlay] that falls back to termDb, or to
+// the default [TermDb] if it is nil. The caller should call
+// [TermDbOverlay.Close] when done with it.
+func NewTermDbOverlay(termDb *TermDb) (*TermDbOverlay, error) {
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	if termDb == nil {
+		termDb = TermDbGetDefault()
+	}
+	TermDbRef(termDb)
+
+	return &TermDbOverlay{termDb: termDb, multiplexerRule: DefaultMultiplexerRule}, nil
+}
+
+// Register adds a terminal emulator described by termInfo, used when the
+// environment matches match. Terminals are tried in the order they were
+// registered. The overlay keeps its own reference to termInfo.
+func (o *TermDbOverlay) Register(match TermMatch, termInfo *TermInfo) {
+	o.register(&o.terminals, match, termInfo)
+}
+
+// RegisterProfile is like [TermDbOverlay.Register], for a terminal described
+// by a [TermProfile].
+func (o *TermDbOverlay) RegisterProfile(match TermMatch, profile *TermProfile) error {
+	termInfo, err := profile.TermInfo()
+	if err != nil {
+		return err
+	}
+	defer TermInfoUnref(termInfo)
+
+	o.Register(match, termInfo)
+	return nil
+}
+
+// RegisterMultiplexer adds a multiplexer, or other program running inside the
+// terminal that changes its capabilities, described by termInfo. When the
+// environment matches match, it is chained onto the detected terminal with
+// [TermInfoChain], termInfo being the inner one, so its inherit flags decide
+// which of the terminal's sequences are kept. Every matching multiplexer is
+// chained, in the order they were registered.
+func (o *TermDbOverlay) RegisterMultiplexer(match TermMatch, termInfo *TermInfo) {
+	o.register(&o.multiplexers, match, termInfo)
+}
+
+// SetMultiplexerRule sets the rule by which [TermDbOverlay.Detect] decides that
+// a registered terminal runs inside a multiplexer libchafa knows. The default is
+// [DefaultMultiplexerRule].
+//
+// A nil rule turns this off, so registered terminals are always used as they
+// are. Multiplexers can then be described with [TermDbOverlay.RegisterMultiplexer]
+// instead, which also covers those libchafa does not know.
+func (o *TermDbOverlay) SetMultiplexerRule(rule MultiplexerRule) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.multiplexerRule = rule
+}
+
+func (o *TermDbOverlay) register(entries *[]overlayEntry, match TermMatch, termInfo *TermInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+
+	TermInfoRef(termInfo)
+	*entries = append(*entries, overlayEntry{match: match, termInfo: termInfo})
+}
+
+// Detect builds a new [TermInfo] for the environment envp, like
+// [TermDbDetect]. The first registered terminal matching envp is used, and
+// otherwise the overlay's [TermDb].
+//
+// When a registered terminal runs inside a multiplexer that libchafa knows,
+// as decided by the overlay's [MultiplexerRule], libchafa's detection is used,
+// supplemented with the registered terminal's sequences. This way graphics
+// sequences that the terminal supports are sent through the multiplexer's
+// passthrough.
+//
+// Matching multiplexers registered with [TermDbOverlay.RegisterMultiplexer]
+// are then chained onto the result. The caller should unref it when done.
+func (o *TermDbOverlay) Detect(envp []string) *TermInfo {
+	env := make(map[string]string, len(envp))
+	for _, kv := range envp {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	getenv := func(key string) string {
+		return env[key]
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	termDb := o.termDb
+	if o.closed {
+		termDb = TermDbGetDefault()
+	}
+
+	var termInfo *TermInfo
+	for _, e := range o.terminals {
+		if !e.match.matches(env) {
+			continue
+		}
+
+		if o.multiplexerRule != nil && o.multiplexerRule(getenv) {
+			termInfo = TermDbDetect(termDb, envp)
+			TermInfoSupplement(termInfo, e.termInfo)
+		} else {
+			termInfo = TermInfoCopy(e.termInfo)
+		}
+		break
+	}
+
+	if termInfo == nil {
+		termInfo = TermDbDetect(termDb, envp)
+	}
+
+	for _, e := range o.multiplexers {
+		if !e.match.matches(env) {
+			continue
+		}
+
+		chained := TermInfoChain(termInfo, e.termInfo)
+		TermInfoUnref(termInfo)
+		termInfo = chained
+	}
+
+	return termInfo
+}
+
+// Close releases the registered terminals and the [TermDb]. Further
+// registrations are ignored. It is safe to call Close more than once.
+func (o *TermDbOverlay) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+
+	for _, entries := range [][]overlayEntry{o.terminals, o.multiplexers} {
+		for _, e := range entries {
+			TermInfoUnref(e.termInfo)
+		}
+	}
+	o.terminals, o.multiplexers = nil, nil
+	TermDbUnref(o.termDb)
+
+	return nil
+}
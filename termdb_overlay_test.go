@@ -0,0 +1,94 @@
+package chafa
+
+import "testing"
+
+// namedTermInfo returns a blank TermInfo called name.
+func namedTermInfo(name string) *TermInfo {
+	termInfo := TermInfoNew()
+	TermInfoSetName(termInfo, name)
+	return termInfo
+}
+
+func newTestOverlay(t *testing.T) *TermDbOverlay {
+	t.Helper()
+	requireLibrary(t)
+
+	overlay, err := NewTermDbOverlay(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { overlay.Close() })
+
+	for _, name := range []string{"webterm", "webterm-beta"} {
+		termInfo := namedTermInfo(name)
+		overlay.Register(TermMatch{"TERM_PROGRAM": name + "*"}, termInfo)
+		TermInfoUnref(termInfo)
+	}
+	return overlay
+}
+
+func detectedName(overlay *TermDbOverlay, envp ...string) string {
+	termInfo := overlay.Detect(envp)
+	defer TermInfoUnref(termInfo)
+
+	return TermInfoGetName(termInfo)
+}
+
+func TestTermDbOverlayRegistrationOrder(t *testing.T) {
+	overlay := newTestOverlay(t)
+
+	// "webterm*" also matches webterm-beta, and was registered first
+	tests := map[string]string{
+		"TERM_PROGRAM=webterm":      "webterm",
+		"TERM_PROGRAM=webterm-beta": "webterm",
+	}
+	for env, want := range tests {
+		if got := detectedName(overlay, env, "TERM=xterm-256color"); got != want {
+			t.Errorf("%s: detected %q, want %q", env, got, want)
+		}
+	}
+
+	for _, name := range []string{"outer", "inner"} {
+		termInfo := namedTermInfo(name)
+		overlay.RegisterMultiplexer(TermMatch{"MUX": "on"}, termInfo)
+		TermInfoUnref(termInfo)
+	}
+	if got, want := detectedName(overlay, "TERM_PROGRAM=webterm", "MUX=on"), "inner:outer:webterm"; got != want {
+		t.Errorf("chained multiplexers: detected %q, want %q", got, want)
+	}
+}
+
+func TestTermDbOverlayFallback(t *testing.T) {
+	overlay := newTestOverlay(t)
+
+	envp := []string{"TERM=xterm-256color", "TERM_PROGRAM=other"}
+	builtin := TermDbDetect(TermDbGetDefault(), envp)
+	want := TermInfoGetName(builtin)
+	TermInfoUnref(builtin)
+
+	if got := detectedName(overlay, envp...); got != want {
+		t.Errorf("unregistered terminal: detected %q, want %q", got, want)
+	}
+}
+
+func TestTermDbOverlayMultiplexerRule(t *testing.T) {
+	overlay := newTestOverlay(t)
+
+	// No TMUX variable, only the TERM tmux sets
+	envp := []string{"TERM=tmux-256color", "TERM_PROGRAM=webterm"}
+	if got := detectedName(overlay, envp...); got == "webterm" {
+		t.Errorf("inside tmux: detected %q, want libchafa's detection", got)
+	}
+
+	overlay.SetMultiplexerRule(nil)
+	if got := detectedName(overlay, envp...); got != "webterm" {
+		t.Errorf("without a multiplexer rule: detected %q, want webterm", got)
+	}
+
+	overlay.SetMultiplexerRule(func(getenv func(string) string) bool {
+		return getenv("ZELLIJ") != ""
+	})
+	if got := detectedName(overlay, "TERM=xterm-256color", "TERM_PROGRAM=webterm", "ZELLIJ=0"); got == "webterm" {
+		t.Errorf("custom multiplexer rule: detected %q, want libchafa's detection", got)
+	}
+}
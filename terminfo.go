@@ -1,5 +1,11 @@
 package chafa
 
+import (
+	"fmt"
+	"strconv"
+	"unsafe"
+)
+
 var (
 	// Creates a new, blank [TermInfo].
 	TermInfoNew func() *TermInfo
@@ -25,12 +31,16 @@ var (
 	// The string's length after formatting must not exceed [CHAFA_TERM_SEQ_LENGTH_MAX] bytes.
 	// Each argument can add up to four digits, or three for those specified as 8-bit integers.
 	// If the string could potentially exceed this length when formatted,
-	// [TermInfoSetSeq] will return FALSE.
+	// this will return FALSE.
 	//
 	// If parsing fails or str is too long, any previously existing sequence will be left untouched.
 	//
 	// Passing NULL for str clears the corresponding control sequence.
-	TermInfoSetSeq func(termInfo *TermInfo, seq TermSeq, str string, err **GError) bool // TODO:
+	termInfoSetSeq func(termInfo *TermInfo, seq TermSeq, str *byte, err **GError) bool
+
+	// Returns the error domain of errors reported by [TermInfo] functions, whose
+	// codes are [TermInfoError] values.
+	TermInfoErrorQuark func() uint32
 
 	// Checks if termInfo can emit seq.
 	TermInfoHaveSeq func(termInfo *TermInfo, seq TermSeq) bool
@@ -67,386 +77,450 @@ var (
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetTerminalSoft func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetTerminalSoft func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_TERMINAL_HARD].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetTerminalHard func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetTerminalHard func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_ATTRIBUTES].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetAttributes func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetAttributes func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CLEAR].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitClear func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitClear func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_TO_POS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorToPos func(termInfo *TermInfo, dest *string, x, y uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorToPos func(termInfo *TermInfo, dest *byte, x, y uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_TO_TOP_LEFT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorToTopLeft func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorToTopLeft func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_TO_BOTTOM_LEFT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorToBottomLeft func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorToBottomLeft func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_UP].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorUp func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorUp func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_DOWN].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorDown func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorDown func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_LEFT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorLeft func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorLeft func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_RIGHT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorRight func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorRight func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_UP_1].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorUp1 func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorUp1 func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_DOWN_1].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorDown1 func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorDown1 func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_LEFT_1].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorLeft1 func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorLeft1 func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_RIGHT_1].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorRight1 func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorRight1 func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_UP_SCROLL].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorUpScroll func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorUpScroll func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_CURSOR_DOWN_SCROLL].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCursorDownScroll func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCursorDownScroll func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_INSERT_CELLS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitInsertCells func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitInsertCells func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DELETE_CELLS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDeleteCells func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDeleteCells func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_INSERT_ROWS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitInsertRows func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitInsertRows func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DELETE_ROWS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDeleteRows func(termInfo *TermInfo, dest *string, n uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDeleteRows func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_CURSOR].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableCursor func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableCursor func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DISABLE_CURSOR].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDisableCursor func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDisableCursor func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_ECHO].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableEcho func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableEcho func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DISABLE_ECHO].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDisableEcho func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDisableEcho func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_INSERT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableInsert func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableInsert func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DISABLE_INSERT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDisableInsert func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDisableInsert func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_WRAP].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableWrap func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableWrap func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DISABLE_WRAP].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDisableWrap func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDisableWrap func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_BOLD].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableBold func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableBold func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_INVERT_COLORS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitInvertColors func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitInvertColors func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_BG_8].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorBg8 func(termInfo *TermInfo, dest *string, pen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorBg8 func(termInfo *TermInfo, dest *byte, pen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FG_8].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFg8 func(termInfo *TermInfo, dest *string, pen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFg8 func(termInfo *TermInfo, dest *byte, pen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FGBG_8].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFgbg8 func(termInfo *TermInfo, dest *string, fgPen, bgPen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFgbg8 func(termInfo *TermInfo, dest *byte, fgPen, bgPen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FG_16].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFg16 func(termInfo *TermInfo, dest *string, pen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFg16 func(termInfo *TermInfo, dest *byte, pen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_BG_16].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorBg16 func(termInfo *TermInfo, dest *string, pen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorBg16 func(termInfo *TermInfo, dest *byte, pen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FGBG_16].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFgbg16 func(termInfo *TermInfo, dest *string, fgPen, bgPen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFgbg16 func(termInfo *TermInfo, dest *byte, fgPen, bgPen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FG_256].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFg256 func(termInfo *TermInfo, dest *string, pen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFg256 func(termInfo *TermInfo, dest *byte, pen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_BG_256].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorBg256 func(termInfo *TermInfo, dest *string, pen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorBg256 func(termInfo *TermInfo, dest *byte, pen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FGBG_256].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFgbg256 func(termInfo *TermInfo, dest *string, fgPen, bgPen uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFgbg256 func(termInfo *TermInfo, dest *byte, fgPen, bgPen uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FG_DIRECT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFgDirect func(termInfo *TermInfo, dest *string, r, g, b uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFgDirect func(termInfo *TermInfo, dest *byte, r, g, b uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_BG_DIRECT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorBgDirect func(termInfo *TermInfo, dest *string, r, g, b uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorBgDirect func(termInfo *TermInfo, dest *byte, r, g, b uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_COLOR_FGBG_DIRECT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetColorFgbgDirect func(termInfo *TermInfo, dest *string, fgR, fgG, fgB, bgR, bgG, bgB uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetColorFgbgDirect func(termInfo *TermInfo, dest *byte, fgR, fgG, fgB, bgR, bgG, bgB uint8) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_COLOR_FG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetColorFg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetColorFg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_COLOR_BG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetColorBg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetColorBg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_COLOR_FGBG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetColorFgbg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetColorFgbg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_DEFAULT_FG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetDefaultFg func(termInfo *TermInfo, dest *string, r, g, b uint16) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetDefaultFg func(termInfo *TermInfo, dest *byte, r, g, b uint16) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_DEFAULT_BG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetDefaultBg func(termInfo *TermInfo, dest *string, r, g, b uint16) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetDefaultBg func(termInfo *TermInfo, dest *byte, r, g, b uint16) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_DEFAULT_FG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetDefaultFg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetDefaultFg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_DEFAULT_BG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetDefaultBg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetDefaultBg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_QUERY_DEFAULT_FG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitQueryDefaultFg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitQueryDefaultFg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_QUERY_DEFAULT_BG].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitQueryDefaultBg func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitQueryDefaultBg func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_REPEAT_CHAR].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitRepeatChar func(termInfo *TermInfo, dest *string, n uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitRepeatChar func(termInfo *TermInfo, dest *byte, n uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_SCROLLING_ROWS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetScrollingRows func(termInfo *TermInfo, dest *string, top, bottom uint8) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetScrollingRows func(termInfo *TermInfo, dest *byte, top, bottom int32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESET_SCROLLING_ROWS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitResetScrollingRows func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitResetScrollingRows func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SAVE_CURSOR_POS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSaveCursorPos func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSaveCursorPos func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RESTORE_CURSOR_POS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitRestoreCursorPos func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitRestoreCursorPos func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_SIXELS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// All three parameters (p1 , p2 and p3 ) can normally be set to 0.
-	TermInfoEmitBeginSixels func(termInfo *TermInfo, dest *string, p1, p2, p3 uint8) string
+	TermInfoEmitBeginSixels func(termInfo *TermInfo, dest *byte, p1, p2, p3 uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_SIXELS].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndSixels func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndSixels func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_SIXEL_SCROLLING].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableSixelScrolling func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableSixelScrolling func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DISABLE_SIXEL_SCROLLING].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDisableSixelScrolling func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDisableSixelScrolling func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_SIXEL_ADVANCE_DOWN].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetSixelAdvanceDown func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetSixelAdvanceDown func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_SET_SIXEL_ADVANCE_RIGHT].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitSetSixelAdvanceRight func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitSetSixelAdvanceRight func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_KITTY_IMMEDIATE_IMAGE_V1].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// bpp must be set to either 24 for RGB data, 32 for RGBA, or 100 to embed a PNG file.
 	//
@@ -457,14 +531,15 @@ var (
 	// When the image data has been transferred, [CHAFA_TERM_SEQ_END_KITTY_IMAGE] must be emitted.
 	TermInfoEmitBeginKittyImmediateImageV1 func(
 		termInfo *TermInfo,
-		dest *string,
-		bpp, widthPixels, heightPixels, widthCells, heightCells uint8,
-	) string
+		dest *byte,
+		bpp, widthPixels, heightPixels, widthCells, heightCells uint32,
+	) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_KITTY_IMMEDIATE_IMAGE_V1].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// bpp must be set to either 24 for RGB data, 32 for RGBA, or 100 to embed a PNG file.
 	//
@@ -475,511 +550,592 @@ var (
 	// When the image data has been transferred, [CHAFA_TERM_SEQ_END_KITTY_IMAGE] must be emitted.
 	TermInfoEmitBeginKittyImmediateVirtImageV1 func(
 		termInfo *TermInfo,
-		dest *string,
-		bpp, widthPixels, heightPixels, widthCells, heightCells, id uint8,
-	) string
+		dest *byte,
+		bpp, widthPixels, heightPixels, widthCells, heightCells, id uint32,
+	) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_KITTY_IMAGE].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndKittyImage func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndKittyImage func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_KITTY_IMAGE_CHUNK].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitBeginKittyImageChunk func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitBeginKittyImageChunk func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_KITTY_IMAGE_CHUNK].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndKittyImageChunk func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndKittyImageChunk func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_ITERM2_IMAGE].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// This sequence must be followed by base64-encoded image file data. The image
 	// can be any format supported by MacOS, e.g. PNG, JPEG, TIFF, GIF. When the
 	// image data has been transferred, [CHAFA_TERM_SEQ_END_ITERM2_IMAGE] must be emitted.
-	TermInfoEmitBeginIterm2Image func(termInfo *TermInfo, dest *string, width, height uint8) string
+	TermInfoEmitBeginIterm2Image func(termInfo *TermInfo, dest *byte, width, height uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_ITERM2_IMAGE].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndIterm2Image func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndIterm2Image func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_SCREEN_PASSTHROUGH].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// Any control sequences between the beginning and end passthrough seqs must
 	// be escaped by turning \033 into \033\033.
-	TermInfoEmitBeginScreenPassthrough func(termInfo *TermInfo, dest *string) string
+	TermInfoEmitBeginScreenPassthrough func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_SCREEN_PASSTHROUGH].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// Any control sequences between the beginning and end passthrough seqs must
 	// be escaped by turning \033 into \033\033.
-	TermInfoEmitEndScreenPassthrough func(termInfo *TermInfo, dest *string) string
+	TermInfoEmitEndScreenPassthrough func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_ENABLE_ALT_SCREEN].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEnableAltScreen func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEnableAltScreen func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DISABLE_ALT_SCREEN].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDisableAltScreen func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDisableAltScreen func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BEGIN_TMUX_PASSTHROUGH].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// Any control sequences between the beginning and end passthrough seqs must
 	// be escaped by turning \033 into \033\033.
-	TermInfoEmitBeginTmuxPassthrough func(termInfo *TermInfo, dest *string) string
+	TermInfoEmitBeginTmuxPassthrough func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_TMUX_PASSTHROUGH].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
 	//
 	// Any control sequences between the beginning and end passthrough seqs must
 	// be escaped by turning \033 into \033\033.
-	TermInfoEmitEndTmuxPassthrough func(termInfo *TermInfo, dest *string) string
+	TermInfoEmitEndTmuxPassthrough func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RETURN_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitReturnKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitReturnKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_BACKSPACE_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitBackspaceKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitBackspaceKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DELETE_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDeleteKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDeleteKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DELETE_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDeleteCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDeleteCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DELETE_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDeleteShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDeleteShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_INSERT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitInsertKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitInsertKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_INSERT_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitInsertCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitInsertCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_INSERT_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitInsertShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitInsertShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_HOME_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitHomeKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitHomeKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_HOME_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitHomeCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitHomeCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_HOME_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitHomeShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitHomeShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_END_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitEndShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitEndShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_UP_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitUpKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitUpKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_UP_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitUpCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitUpCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_UP_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitUpShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitUpShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DOWN_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDownKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDownKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DOWN_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDownCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDownCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_DOWN_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitDownShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitDownShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_LEFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitLeftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitLeftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_LEFT_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitLeftCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitLeftCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_LEFT_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitLeftShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitLeftShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RIGHT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitRightKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitRightKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RIGHT_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitRightCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitRightCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_RIGHT_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitRightShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitRightShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PAGE_UP_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPageUpKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPageUpKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PAGE_UP_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPageUpCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPageUpCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PAGE_UP_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPageUpShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPageUpShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PAGE_DOWN_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPageDownKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPageDownKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PAGE_DOWN_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPageDownCtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPageDownCtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PAGE_DOWN_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPageDownShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPageDownShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_TAB_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitTabKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitTabKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_TAB_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitTabShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitTabShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F1_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF1Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF1Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F1_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF1CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF1CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F1_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF1ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF1ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F2_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF2Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF2Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F2_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF2CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF2CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F2_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF2ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF2ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F3_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF3Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF3Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F3_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF3CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF3CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F3_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF3ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF3ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F4_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF4Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF4Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F4_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF4CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF4CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F4_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF4ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF4ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F5_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF5Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF5Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F5_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF5CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF5CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F5_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF5ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF5ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F6_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF6Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF6Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F6_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF6CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF6CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F6_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF6ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF6ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F7_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF7Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF7Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F7_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF7CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF7CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F7_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF7ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF7ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F8_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF8Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF8Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F8_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF8CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF8CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F8_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF8ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF8ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F9_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF9Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF9Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F9_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF9CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF9CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F9_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF9ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF9ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F10_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF10Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF10Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F10_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF10CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF10CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F10_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF10ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF10ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F11_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF11Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF11Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F11_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF11CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF11CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F11_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF11ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF11ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F12_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF12Key func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF12Key func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F12_CTRL_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF12CtrlKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF12CtrlKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_F12_SHIFT_KEY].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitF12ShiftKey func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitF12ShiftKey func(termInfo *TermInfo, dest *byte) *byte
 
 	// Terminal emulators and applications are often nested, with the inner
 	// application's capabilities limiting, extending or modifying the outer's.
@@ -1014,52 +1170,60 @@ var (
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitCellSizePx func(termInfo *TermInfo, dest *string, heightPx, widthPx uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitCellSizePx func(termInfo *TermInfo, dest *byte, heightPx, widthPx uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_PRIMARY_DEVICE_ATTRIBUTES].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitPrimaryDeviceAttributes func(termInfo *TermInfo, dest *string, args *uint32, nArgs int32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitPrimaryDeviceAttributes func(termInfo *TermInfo, dest *byte, args *uint32, nArgs int32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_QUERY_CELL_SIZE_PX].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitQueryCellSizePx func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitQueryCellSizePx func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_QUERY_PRIMARY_DEVICE_ATTRIBUTES].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitQueryPrimaryDeviceAttributes func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitQueryPrimaryDeviceAttributes func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_CELLS].
 	//
-	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX bytes],
+	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitQueryTextAreaSizeCells func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitQueryTextAreaSizeCells func(termInfo *TermInfo, dest *byte) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_PX].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitQueryTextAreaSizePx func(termInfo *TermInfo, dest *string) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitQueryTextAreaSizePx func(termInfo *TermInfo, dest *byte) *byte
 
 	// Behaves like [TermInfoEmitSeq]
 	TermInfoEmitSeqValist func(termInfo *TermInfo, seq TermSeq, args ...any) string
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_TEXT_AREA_SIZE_CELLS].
 	//
-	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX bytes],
+	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitTextAreaSizeCells func(termInfo *TermInfo, dest *string, heightCells, widthCells uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitTextAreaSizeCells func(termInfo *TermInfo, dest *byte, heightCells, widthCells uint32) *byte
 
 	// Prints the control sequence for [CHAFA_TERM_SEQ_TEXT_AREA_SIZE_PX].
 	//
 	// dest must have enough space to hold [CHAFA_TERM_SEQ_LENGTH_MAX] bytes,
 	// even if the emitted sequence is shorter. The output will not be zero-terminated.
-	TermInfoEmitTextAreaSizePx func(termInfo *TermInfo, dest *string, heightPx, widthPx uint32) string
+	// Returns a pointer to the byte after the sequence; see [TermInfoEmitString].
+	TermInfoEmitTextAreaSizePx func(termInfo *TermInfo, dest *byte, heightPx, widthPx uint32) *byte
 
 	// Gets the optimal [CanvasMode] supported by termInfo.
 	TermInfoGetBestCanvasMode func(termInfo *TermInfo) CanvasMode
@@ -1137,12 +1301,95 @@ var (
 	TermInfoSetSafeSymbolTags func(termInfo *TermInfo, tags SymbolTags)
 )
 
+// Sets the control sequence string equivalent of seq stored in termInfo to str.
+//
+// The string may contain argument indexes to be substituted with integers on
+// formatting. The indexes are preceded by a percentage character and
+// start at 1, i.e. %1, %2, %3, etc.
+//
+// The string's length after formatting must not exceed [CHAFA_TERM_SEQ_LENGTH_MAX] bytes.
+// Each argument can add up to four digits, or three for those specified as 8-bit integers.
+//
+// If parsing fails or str is too long, any previously existing sequence is left
+// untouched and an [*Error] with a [TermInfoError] code is returned. Some
+// invalid escapes, such as %9, are rejected by libchafa without a reason; the
+// code is then worked out from str.
+//
+// Passing an empty str clears the corresponding control sequence.
+func TermInfoSetSeq(termInfo *TermInfo, seq TermSeq, str string) error {
+	if err := Init(); err != nil {
+		return err
+	}
+
+	var cstr *byte
+	if str != "" {
+		cstr = cString(str)
+	}
+
+	var gerr *GError
+	if !termInfoSetSeq(termInfo, seq, cstr, &gerr) {
+		if err := gErrorToGo(gerr); err != nil {
+			return err
+		}
+
+		code := seqErrorCode(str)
+		err := &Error{Code: int32(code), Message: fmt.Sprintf("invalid control sequence %q", str)}
+		if HasFunction("chafa_term_info_error_quark") {
+			err.Domain = TermInfoErrorQuark()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// seqErrorCode guesses why libchafa rejected the control sequence str without
+// setting an error. If every escape in it is an argument index, one of them
+// must be out of range for the sequence.
+func seqErrorCode(str string) TermInfoError {
+	for i := 0; i < len(str); i++ {
+		if str[i] != '%' {
+			continue
+		}
+		i++
+		if i == len(str) || (str[i] != '%' && (str[i] < '0' || str[i] > '9')) {
+			return CHAFA_TERM_INFO_ERROR_BAD_ESCAPE
+		}
+	}
+
+	return CHAFA_TERM_INFO_ERROR_BAD_ARGUMENTS
+}
+
+// Calls emit with a buffer of [CHAFA_TERM_SEQ_LENGTH_MAX] bytes and returns the
+// control sequence it printed there. emit is expected to pass the buffer as dest
+// to one of the TermInfoEmit functions and return its result, e.g.:
+//
+//	seq := TermInfoEmitString(func(dest *byte) *byte {
+//		return TermInfoEmitCursorToPos(termInfo, dest, x, y)
+//	})
+//
+// An empty string is returned if emit returns nil, which the emit functions do
+// when libchafa could not be loaded or lacks them.
+func TermInfoEmitString(emit func(dest *byte) *byte) string {
+	var buf [CHAFA_TERM_SEQ_LENGTH_MAX]byte
+	end := emit(&buf[0])
+	if end == nil {
+		return ""
+	}
+
+	n := uintptr(unsafe.Pointer(end)) - uintptr(unsafe.Pointer(&buf[0]))
+	if n > uintptr(len(buf)) {
+		return ""
+	}
+	return string(buf[:n])
+}
+
 type TermInfo struct {
 	Refs                   int32
-	Name                   string
+	Name                   *byte
 	SeqStr                 [CHAFA_TERM_SEQ_MAX][CHAFA_TERM_SEQ_LENGTH_MAX]byte
 	SeqArgs                [CHAFA_TERM_SEQ_MAX][CHAFA_TERM_SEQ_ARGS_MAX]SeqArgInfo
-	UnparsedStr            [CHAFA_TERM_SEQ_MAX]string
+	UnparsedStr            [CHAFA_TERM_SEQ_MAX]*byte
 	PixelPassthroughNeeded [CHAFA_PIXEL_MODE_MAX]uint8
 	InheritSeq             [CHAFA_TERM_SEQ_MAX]uint8
 	Quirks                 TermQuirks
@@ -1150,9 +1397,9 @@ type TermInfo struct {
 }
 
 type SeqArgInfo struct {
-	IsVarargs bool
-	PreLen    uint8
-	ArgIndex  uint8
+	// C bit fields: is_varargs is bit 0, pre_len is bits 1-7.
+	Flags    uint8
+	ArgIndex uint8
 }
 
 type TermSeq int32
@@ -1325,6 +1572,86 @@ const (
 	CHAFA_TERM_QUIRK_SIXEL_OVERSHOOT TermQuirks = (1 << 0)
 )
 
+// formatSeq formats the control sequence seq from termInfo, substituting its
+// argument indexes (%1, %2, ...) with args. A %v index takes all remaining
+// arguments, separated by semicolons, as used by
+// [CHAFA_TERM_SEQ_PRIMARY_DEVICE_ATTRIBUTES].
+//
+// The arguments are inserted as given. Unlike the chafa_term_info_emit_*()
+// functions, no offsets are applied, so e.g. the coordinates for
+// [CHAFA_TERM_SEQ_CURSOR_TO_POS] must be one-based.
+//
+// An error is returned if termInfo lacks seq or an argument is missing.
+func formatSeq(termInfo *TermInfo, seq TermSeq, args ...uint32) (string, error) {
+	if !TermInfoHaveSeq(termInfo, seq) {
+		return "", fmt.Errorf("chafa: terminal has no sequence %d", seq)
+	}
+
+	tmpl := TermInfoGetSeq(termInfo, seq)
+	buf := make([]byte, 0, len(tmpl)+8)
+
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '%' || i+1 == len(tmpl) {
+			buf = append(buf, tmpl[i])
+			continue
+		}
+
+		i++
+		switch c := tmpl[i]; {
+		case c == '%':
+			buf = append(buf, '%')
+
+		case c == 'v':
+			for j, arg := range args {
+				if j > 0 {
+					buf = append(buf, ';')
+				}
+				buf = strconv.AppendUint(buf, uint64(arg), 10)
+			}
+
+		case c >= '1' && c <= '9':
+			n := int(c - '1')
+			if n >= len(args) {
+				return "", fmt.Errorf("chafa: sequence %d needs argument %d, got %d", seq, n+1, len(args))
+			}
+			buf = strconv.AppendUint(buf, uint64(args[n]), 10)
+
+		default:
+			buf = append(buf, '%', c)
+		}
+	}
+
+	return string(buf), nil
+}
+
+// TermInfoError is the code of an [*Error] in the [TermInfoErrorQuark] domain.
+// It can be used as the target of errors.Is.
+type TermInfoError int32
+
+const (
+	// A control sequence could exceed [CHAFA_TERM_SEQ_LENGTH_MAX] bytes if formatted with maximum argument lengths.
+	CHAFA_TERM_INFO_ERROR_SEQ_TOO_LONG TermInfoError = 0
+
+	// An illegal escape sequence was used.
+	CHAFA_TERM_INFO_ERROR_BAD_ESCAPE TermInfoError = 1
+
+	// A control sequence specified more than the maximum number of arguments, or an argument index was out of range.
+	CHAFA_TERM_INFO_ERROR_BAD_ARGUMENTS TermInfoError = 2
+)
+
+func (e TermInfoError) Error() string {
+	switch e {
+	case CHAFA_TERM_INFO_ERROR_SEQ_TOO_LONG:
+		return "chafa: control sequence too long"
+	case CHAFA_TERM_INFO_ERROR_BAD_ESCAPE:
+		return "chafa: bad escape in control sequence"
+	case CHAFA_TERM_INFO_ERROR_BAD_ARGUMENTS:
+		return "chafa: bad arguments in control sequence"
+	default:
+		return fmt.Sprintf("chafa: term info error %d", int32(e))
+	}
+}
+
 type ParseResult int32
 
 const (
@@ -0,0 +1,119 @@
+package chafa
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestTermInfoEmitString(t *testing.T) {
+	requireLibrary(t)
+
+	termInfo := TermDbDetect(TermDbGetDefault(), []string{"TERM=xterm-kitty", "KITTY_WINDOW_ID=1"})
+	defer TermInfoUnref(termInfo)
+
+	tests := []struct {
+		name string
+		emit func(dest *byte) *byte
+		want string
+	}{
+		{"cursor to pos", func(dest *byte) *byte {
+			return TermInfoEmitCursorToPos(termInfo, dest, 4, 2)
+		}, "\x1b[3;5H"},
+		{"direct color", func(dest *byte) *byte {
+			return TermInfoEmitSetColorFgDirect(termInfo, dest, 1, 2, 3)
+		}, "\x1b[38;2;1;2;3m"},
+		{"nil", func(dest *byte) *byte { return nil }, ""},
+	}
+	for _, tt := range tests {
+		if got := TermInfoEmitString(tt.emit); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Sizes above 255 must not be truncated
+	got := TermInfoEmitString(func(dest *byte) *byte {
+		return TermInfoEmitBeginKittyImmediateImageV1(termInfo, dest, 32, 1000, 700, 80, 24)
+	})
+	if !strings.Contains(got, "s=1000") || !strings.Contains(got, "v=700") {
+		t.Errorf("kitty image sequence %q lacks the pixel size", got)
+	}
+}
+
+func TestTermInfoSetSeqError(t *testing.T) {
+	requireLibrary(t)
+
+	freed := 0
+	free := gErrorFree
+	gErrorFree = func(err *GError) {
+		freed++
+		free(err)
+	}
+	defer func() { gErrorFree = free }()
+
+	termInfo := TermInfoNew()
+	defer TermInfoUnref(termInfo)
+
+	if err := TermInfoSetSeq(termInfo, CHAFA_TERM_SEQ_CURSOR_TO_POS, "\x1b[%2;%1H"); err != nil {
+		t.Fatal(err)
+	}
+
+	// libchafa sets a GError for some failures only
+	tests := []struct {
+		name   string
+		str    string
+		code   TermInfoError
+		gerror bool
+	}{
+		{"index out of range", "\x1b[%9H", CHAFA_TERM_INFO_ERROR_BAD_ARGUMENTS, false},
+		{"bad escape", "\x1b[%xH", CHAFA_TERM_INFO_ERROR_BAD_ESCAPE, false},
+		{"trailing percent", "\x1b[%", CHAFA_TERM_INFO_ERROR_BAD_ESCAPE, false},
+		{"too many arguments", "\x1b[%1;%2;%3H", CHAFA_TERM_INFO_ERROR_BAD_ARGUMENTS, true},
+		{"too long", strings.Repeat("x", CHAFA_TERM_SEQ_LENGTH_MAX+1), CHAFA_TERM_INFO_ERROR_SEQ_TOO_LONG, true},
+	}
+	for _, tt := range tests {
+		freed = 0
+		err := TermInfoSetSeq(termInfo, CHAFA_TERM_SEQ_CURSOR_TO_POS, tt.str)
+
+		var chafaErr *Error
+		if !errors.As(err, &chafaErr) || chafaErr.Domain != TermInfoErrorQuark() || chafaErr.Code != int32(tt.code) {
+			t.Errorf("%s: got %#v, want an *Error with code %d", tt.name, err, tt.code)
+		}
+		if !errors.Is(err, tt.code) {
+			t.Errorf("%s: errors.Is(%v, %v) is false", tt.name, err, tt.code)
+		}
+		if want := map[bool]int{false: 0, true: 1}[tt.gerror]; freed != want {
+			t.Errorf("%s: GError freed %d times, want %d", tt.name, freed, want)
+		}
+	}
+
+	if got := TermInfoGetSeq(termInfo, CHAFA_TERM_SEQ_CURSOR_TO_POS); got != "\x1b[%2;%1H" {
+		t.Errorf("failed calls replaced the sequence with %q", got)
+	}
+}
+
+func TestTermInfoEmitIntoBuffer(t *testing.T) {
+	requireLibrary(t)
+
+	termInfo := TermDbDetect(TermDbGetDefault(), []string{"TERM=xterm-kitty", "KITTY_WINDOW_ID=1"})
+	defer TermInfoUnref(termInfo)
+
+	// Emit two sequences back to back, the second at the end of the first.
+	// libchafa may scribble past the end pointer, but only within the
+	// CHAFA_TERM_SEQ_LENGTH_MAX bytes each call may use.
+	buf := bytes.Repeat([]byte{0xff}, 3*CHAFA_TERM_SEQ_LENGTH_MAX)
+	end := TermInfoEmitCursorToPos(termInfo, &buf[0], 4, 2)
+	end = TermInfoEmitSetColorFgDirect(termInfo, end, 1, 2, 3)
+
+	n := int(uintptr(unsafe.Pointer(end)) - uintptr(unsafe.Pointer(&buf[0])))
+	if want := "\x1b[3;5H\x1b[38;2;1;2;3m"; n != len(want) || string(buf[:n]) != want {
+		t.Errorf("end pointer is at %d, buffer holds %q, want %q", n, buf[:n], want)
+	}
+	for i := n + CHAFA_TERM_SEQ_LENGTH_MAX; i < len(buf); i++ {
+		if buf[i] != 0xff {
+			t.Fatalf("byte %d, past the room given to the last call, was overwritten", i)
+		}
+	}
+}
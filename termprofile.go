@@ -0,0 +1,376 @@
+package chafa
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// TermProfile is a plain Go description of a [TermInfo], meant to be stored
+// as JSON, for instance to capture a user's detected terminal for a bug report
+// and replay it later. Sequences, quirks, symbol tags and pixel modes are
+// referred to by name, e.g. "cursor_to_pos", "sixel_overshoot", "block" and
+// "sixels".
+//
+// The fields also carry toml tags for use with a third-party TOML package;
+// this package has no TOML encoder or decoder of its own.
+//
+// Use [NewTermProfile] to export a TermInfo and [TermProfile.TermInfo] to
+// build one.
+type TermProfile struct {
+	// Short lowercase name of the terminal, e.g. "kitty".
+	Name string `json:"name" toml:"name"`
+
+	Quirks []string `json:"quirks,omitempty" toml:"quirks,omitempty"`
+
+	// Symbol tags that are likely safe to use, see [TermInfoGetSafeSymbolTags].
+	SafeSymbolTags []string `json:"safe_symbol_tags,omitempty" toml:"safe_symbol_tags,omitempty"`
+
+	// Pixel modes that need passthrough, see [TermInfoGetIsPixelPassthroughNeeded].
+	PixelPassthroughNeeded []string `json:"pixel_passthrough_needed,omitempty" toml:"pixel_passthrough_needed,omitempty"`
+
+	// Sequences that can be inherited when chained, see [TermInfoGetInheritSeq].
+	Inherit []string `json:"inherit,omitempty" toml:"inherit,omitempty"`
+
+	// Control sequences by name, in the format of [TermInfoGetSeq].
+	Seqs map[string]string `json:"seqs" toml:"seqs"`
+}
+
+// NewTermProfile exports termInfo as a [TermProfile].
+func NewTermProfile(termInfo *TermInfo) *TermProfile {
+	p := &TermProfile{
+		Name: TermInfoGetName(termInfo),
+		Seqs: make(map[string]string),
+	}
+
+	p.Quirks = flagNames(uint32(TermInfoGetQuirks(termInfo)), termQuirkNames)
+	p.SafeSymbolTags = flagNames(uint32(TermInfoGetSafeSymbolTags(termInfo)), symbolTagNames)
+
+	for mode := CHAFA_PIXEL_MODE_SYMBOLS; mode < CHAFA_PIXEL_MODE_MAX; mode++ {
+		if TermInfoGetIsPixelPassthroughNeeded(termInfo, mode) {
+			p.PixelPassthroughNeeded = append(p.PixelPassthroughNeeded, pixelModeNames[mode])
+		}
+	}
+
+	for seq := TermSeq(0); seq < CHAFA_TERM_SEQ_MAX; seq++ {
+		if TermInfoHaveSeq(termInfo, seq) {
+			p.Seqs[seq.String()] = TermInfoGetSeq(termInfo, seq)
+		}
+		if TermInfoGetInheritSeq(termInfo, seq) {
+			p.Inherit = append(p.Inherit, seq.String())
+		}
+	}
+
+	return p
+}
+
+// TermInfo builds a new [TermInfo] from the profile. An error is returned
+// if the profile refers to an unknown name or has an invalid sequence. The
+// caller should unref the TermInfo when done.
+func (p *TermProfile) TermInfo() (*TermInfo, error) {
+	quirks, err := parseFlagNames(p.Quirks, termQuirkNames, "quirk")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := parseFlagNames(p.SafeSymbolTags, symbolTagNames, "symbol tag")
+	if err != nil {
+		return nil, err
+	}
+
+	var passthrough []PixelMode
+	for _, name := range p.PixelPassthroughNeeded {
+		i := slices.Index(pixelModeNames[:], name)
+		if i < 0 {
+			return nil, fmt.Errorf("chafa: unknown pixel mode %q", name)
+		}
+		passthrough = append(passthrough, PixelMode(i))
+	}
+
+	var inherit []TermSeq
+	for _, name := range p.Inherit {
+		seq, err := ParseTermSeq(name)
+		if err != nil {
+			return nil, err
+		}
+		inherit = append(inherit, seq)
+	}
+
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	termInfo := TermInfoNew()
+	TermInfoSetName(termInfo, p.Name)
+	TermInfoSetQuirks(termInfo, TermQuirks(quirks))
+	TermInfoSetSafeSymbolTags(termInfo, SymbolTags(tags))
+	for _, mode := range passthrough {
+		TermInfoSetIsPixelPassthroughNeeded(termInfo, mode, true)
+	}
+	for _, seq := range inherit {
+		TermInfoSetInheritSeq(termInfo, seq, true)
+	}
+
+	for name, str := range p.Seqs {
+		seq, err := ParseTermSeq(name)
+		if err != nil {
+			TermInfoUnref(termInfo)
+			return nil, err
+		}
+		if err := TermInfoSetSeq(termInfo, seq, str); err != nil {
+			TermInfoUnref(termInfo)
+			return nil, fmt.Errorf("%w for %s", err, name)
+		}
+	}
+
+	return termInfo, nil
+}
+
+// String returns the name of seq, e.g. "cursor_to_pos".
+func (seq TermSeq) String() string {
+	if seq < 0 || seq >= CHAFA_TERM_SEQ_MAX {
+		return fmt.Sprintf("TermSeq(%d)", int32(seq))
+	}
+	return termSeqNames[seq]
+}
+
+// ParseTermSeq returns the [TermSeq] called name, as returned by
+// [TermSeq.String].
+func ParseTermSeq(name string) (TermSeq, error) {
+	if i := slices.Index(termSeqNames[:], name); i >= 0 {
+		return TermSeq(i), nil
+	}
+	return 0, fmt.Errorf("chafa: unknown terminal sequence %q", name)
+}
+
+// flagNames returns the names of the bits set in flags.
+func flagNames(flags uint32, names map[uint32]string) []string {
+	var out []string
+	for flags != 0 {
+		bit := uint32(1) << bits.TrailingZeros32(flags)
+		if name, ok := names[bit]; ok {
+			out = append(out, name)
+		}
+		flags &^= bit
+	}
+	return out
+}
+
+// parseFlagNames is the inverse of [flagNames].
+func parseFlagNames(list []string, names map[uint32]string, kind string) (uint32, error) {
+	var flags uint32
+
+outer:
+	for _, name := range list {
+		for bit, n := range names {
+			if strings.EqualFold(n, name) {
+				flags |= bit
+				continue outer
+			}
+		}
+		return 0, fmt.Errorf("chafa: unknown %s %q", kind, name)
+	}
+
+	return flags, nil
+}
+
+var termQuirkNames = map[uint32]string{
+	uint32(CHAFA_TERM_QUIRK_SIXEL_OVERSHOOT): "sixel_overshoot",
+}
+
+var symbolTagNames = map[uint32]string{
+	uint32(CHAFA_SYMBOL_TAG_SPACE):     "space",
+	uint32(CHAFA_SYMBOL_TAG_SOLID):     "solid",
+	uint32(CHAFA_SYMBOL_TAG_STIPPLE):   "stipple",
+	uint32(CHAFA_SYMBOL_TAG_BLOCK):     "block",
+	uint32(CHAFA_SYMBOL_TAG_BORDER):    "border",
+	uint32(CHAFA_SYMBOL_TAG_DIAGONAL):  "diagonal",
+	uint32(CHAFA_SYMBOL_TAG_DOT):       "dot",
+	uint32(CHAFA_SYMBOL_TAG_QUAD):      "quad",
+	uint32(CHAFA_SYMBOL_TAG_HHALF):     "hhalf",
+	uint32(CHAFA_SYMBOL_TAG_VHALF):     "vhalf",
+	uint32(CHAFA_SYMBOL_TAG_INVERTED):  "inverted",
+	uint32(CHAFA_SYMBOL_TAG_BRAILLE):   "braille",
+	uint32(CHAFA_SYMBOL_TAG_TECHNICAL): "technical",
+	uint32(CHAFA_SYMBOL_TAG_GEOMETRIC): "geometric",
+	uint32(CHAFA_SYMBOL_TAG_ASCII):     "ascii",
+	uint32(CHAFA_SYMBOL_TAG_ALPHA):     "alpha",
+	uint32(CHAFA_SYMBOL_TAG_DIGIT):     "digit",
+	uint32(CHAFA_SYMBOL_TAG_NARROW):    "narrow",
+	uint32(CHAFA_SYMBOL_TAG_WIDE):      "wide",
+	uint32(CHAFA_SYMBOL_TAG_AMBIGUOUS): "ambiguous",
+	uint32(CHAFA_SYMBOL_TAG_UGLY):      "ugly",
+	uint32(CHAFA_SYMBOL_TAG_LEGACY):    "legacy",
+	uint32(CHAFA_SYMBOL_TAG_SEXTANT):   "sextant",
+	uint32(CHAFA_SYMBOL_TAG_WEDGE):     "wedge",
+	uint32(CHAFA_SYMBOL_TAG_LATIN):     "latin",
+	uint32(CHAFA_SYMBOL_TAG_IMPORTED):  "imported",
+	uint32(CHAFA_SYMBOL_TAG_OCTANT):    "octant",
+	uint32(CHAFA_SYMBOL_TAG_EXTRA):     "extra",
+}
+
+var pixelModeNames = [CHAFA_PIXEL_MODE_MAX]string{
+	CHAFA_PIXEL_MODE_SYMBOLS: "symbols",
+	CHAFA_PIXEL_MODE_SIXELS:  "sixels",
+	CHAFA_PIXEL_MODE_KITTY:   "kitty",
+	CHAFA_PIXEL_MODE_ITERM2:  "iterm2",
+}
+
+// termSeqNames holds the name of every [TermSeq], as used by libchafa's
+// chafa_term_info_emit_*() functions.
+var termSeqNames = [CHAFA_TERM_SEQ_MAX]string{
+	CHAFA_TERM_SEQ_RESET_TERMINAL_SOFT:                 "reset_terminal_soft",
+	CHAFA_TERM_SEQ_RESET_TERMINAL_HARD:                 "reset_terminal_hard",
+	CHAFA_TERM_SEQ_RESET_ATTRIBUTES:                    "reset_attributes",
+	CHAFA_TERM_SEQ_CLEAR:                               "clear",
+	CHAFA_TERM_SEQ_INVERT_COLORS:                       "invert_colors",
+	CHAFA_TERM_SEQ_CURSOR_TO_TOP_LEFT:                  "cursor_to_top_left",
+	CHAFA_TERM_SEQ_CURSOR_TO_BOTTOM_LEFT:               "cursor_to_bottom_left",
+	CHAFA_TERM_SEQ_CURSOR_TO_POS:                       "cursor_to_pos",
+	CHAFA_TERM_SEQ_CURSOR_UP_1:                         "cursor_up_1",
+	CHAFA_TERM_SEQ_CURSOR_UP:                           "cursor_up",
+	CHAFA_TERM_SEQ_CURSOR_DOWN_1:                       "cursor_down_1",
+	CHAFA_TERM_SEQ_CURSOR_DOWN:                         "cursor_down",
+	CHAFA_TERM_SEQ_CURSOR_LEFT_1:                       "cursor_left_1",
+	CHAFA_TERM_SEQ_CURSOR_LEFT:                         "cursor_left",
+	CHAFA_TERM_SEQ_CURSOR_RIGHT_1:                      "cursor_right_1",
+	CHAFA_TERM_SEQ_CURSOR_RIGHT:                        "cursor_right",
+	CHAFA_TERM_SEQ_CURSOR_UP_SCROLL:                    "cursor_up_scroll",
+	CHAFA_TERM_SEQ_CURSOR_DOWN_SCROLL:                  "cursor_down_scroll",
+	CHAFA_TERM_SEQ_INSERT_CELLS:                        "insert_cells",
+	CHAFA_TERM_SEQ_DELETE_CELLS:                        "delete_cells",
+	CHAFA_TERM_SEQ_INSERT_ROWS:                         "insert_rows",
+	CHAFA_TERM_SEQ_DELETE_ROWS:                         "delete_rows",
+	CHAFA_TERM_SEQ_SET_SCROLLING_ROWS:                  "set_scrolling_rows",
+	CHAFA_TERM_SEQ_ENABLE_INSERT:                       "enable_insert",
+	CHAFA_TERM_SEQ_DISABLE_INSERT:                      "disable_insert",
+	CHAFA_TERM_SEQ_ENABLE_CURSOR:                       "enable_cursor",
+	CHAFA_TERM_SEQ_DISABLE_CURSOR:                      "disable_cursor",
+	CHAFA_TERM_SEQ_ENABLE_ECHO:                         "enable_echo",
+	CHAFA_TERM_SEQ_DISABLE_ECHO:                        "disable_echo",
+	CHAFA_TERM_SEQ_ENABLE_WRAP:                         "enable_wrap",
+	CHAFA_TERM_SEQ_DISABLE_WRAP:                        "disable_wrap",
+	CHAFA_TERM_SEQ_SET_COLOR_FG_DIRECT:                 "set_color_fg_direct",
+	CHAFA_TERM_SEQ_SET_COLOR_BG_DIRECT:                 "set_color_bg_direct",
+	CHAFA_TERM_SEQ_SET_COLOR_FGBG_DIRECT:               "set_color_fgbg_direct",
+	CHAFA_TERM_SEQ_SET_COLOR_FG_256:                    "set_color_fg_256",
+	CHAFA_TERM_SEQ_SET_COLOR_BG_256:                    "set_color_bg_256",
+	CHAFA_TERM_SEQ_SET_COLOR_FGBG_256:                  "set_color_fgbg_256",
+	CHAFA_TERM_SEQ_SET_COLOR_FG_16:                     "set_color_fg_16",
+	CHAFA_TERM_SEQ_SET_COLOR_BG_16:                     "set_color_bg_16",
+	CHAFA_TERM_SEQ_SET_COLOR_FGBG_16:                   "set_color_fgbg_16",
+	CHAFA_TERM_SEQ_BEGIN_SIXELS:                        "begin_sixels",
+	CHAFA_TERM_SEQ_END_SIXELS:                          "end_sixels",
+	CHAFA_TERM_SEQ_REPEAT_CHAR:                         "repeat_char",
+	CHAFA_TERM_SEQ_BEGIN_KITTY_IMMEDIATE_IMAGE_V1:      "begin_kitty_immediate_image_v1",
+	CHAFA_TERM_SEQ_END_KITTY_IMAGE:                     "end_kitty_image",
+	CHAFA_TERM_SEQ_BEGIN_KITTY_IMAGE_CHUNK:             "begin_kitty_image_chunk",
+	CHAFA_TERM_SEQ_END_KITTY_IMAGE_CHUNK:               "end_kitty_image_chunk",
+	CHAFA_TERM_SEQ_BEGIN_ITERM2_IMAGE:                  "begin_iterm2_image",
+	CHAFA_TERM_SEQ_END_ITERM2_IMAGE:                    "end_iterm2_image",
+	CHAFA_TERM_SEQ_ENABLE_SIXEL_SCROLLING:              "enable_sixel_scrolling",
+	CHAFA_TERM_SEQ_DISABLE_SIXEL_SCROLLING:             "disable_sixel_scrolling",
+	CHAFA_TERM_SEQ_ENABLE_BOLD:                         "enable_bold",
+	CHAFA_TERM_SEQ_SET_COLOR_FG_8:                      "set_color_fg_8",
+	CHAFA_TERM_SEQ_SET_COLOR_BG_8:                      "set_color_bg_8",
+	CHAFA_TERM_SEQ_SET_COLOR_FGBG_8:                    "set_color_fgbg_8",
+	CHAFA_TERM_SEQ_RESET_DEFAULT_FG:                    "reset_default_fg",
+	CHAFA_TERM_SEQ_SET_DEFAULT_FG:                      "set_default_fg",
+	CHAFA_TERM_SEQ_QUERY_DEFAULT_FG:                    "query_default_fg",
+	CHAFA_TERM_SEQ_RESET_DEFAULT_BG:                    "reset_default_bg",
+	CHAFA_TERM_SEQ_SET_DEFAULT_BG:                      "set_default_bg",
+	CHAFA_TERM_SEQ_QUERY_DEFAULT_BG:                    "query_default_bg",
+	CHAFA_TERM_SEQ_RETURN_KEY:                          "return_key",
+	CHAFA_TERM_SEQ_BACKSPACE_KEY:                       "backspace_key",
+	CHAFA_TERM_SEQ_TAB_KEY:                             "tab_key",
+	CHAFA_TERM_SEQ_TAB_SHIFT_KEY:                       "tab_shift_key",
+	CHAFA_TERM_SEQ_UP_KEY:                              "up_key",
+	CHAFA_TERM_SEQ_UP_CTRL_KEY:                         "up_ctrl_key",
+	CHAFA_TERM_SEQ_UP_SHIFT_KEY:                        "up_shift_key",
+	CHAFA_TERM_SEQ_DOWN_KEY:                            "down_key",
+	CHAFA_TERM_SEQ_DOWN_CTRL_KEY:                       "down_ctrl_key",
+	CHAFA_TERM_SEQ_DOWN_SHIFT_KEY:                      "down_shift_key",
+	CHAFA_TERM_SEQ_LEFT_KEY:                            "left_key",
+	CHAFA_TERM_SEQ_LEFT_CTRL_KEY:                       "left_ctrl_key",
+	CHAFA_TERM_SEQ_LEFT_SHIFT_KEY:                      "left_shift_key",
+	CHAFA_TERM_SEQ_RIGHT_KEY:                           "right_key",
+	CHAFA_TERM_SEQ_RIGHT_CTRL_KEY:                      "right_ctrl_key",
+	CHAFA_TERM_SEQ_RIGHT_SHIFT_KEY:                     "right_shift_key",
+	CHAFA_TERM_SEQ_PAGE_UP_KEY:                         "page_up_key",
+	CHAFA_TERM_SEQ_PAGE_UP_CTRL_KEY:                    "page_up_ctrl_key",
+	CHAFA_TERM_SEQ_PAGE_UP_SHIFT_KEY:                   "page_up_shift_key",
+	CHAFA_TERM_SEQ_PAGE_DOWN_KEY:                       "page_down_key",
+	CHAFA_TERM_SEQ_PAGE_DOWN_CTRL_KEY:                  "page_down_ctrl_key",
+	CHAFA_TERM_SEQ_PAGE_DOWN_SHIFT_KEY:                 "page_down_shift_key",
+	CHAFA_TERM_SEQ_HOME_KEY:                            "home_key",
+	CHAFA_TERM_SEQ_HOME_CTRL_KEY:                       "home_ctrl_key",
+	CHAFA_TERM_SEQ_HOME_SHIFT_KEY:                      "home_shift_key",
+	CHAFA_TERM_SEQ_END_KEY:                             "end_key",
+	CHAFA_TERM_SEQ_END_CTRL_KEY:                        "end_ctrl_key",
+	CHAFA_TERM_SEQ_END_SHIFT_KEY:                       "end_shift_key",
+	CHAFA_TERM_SEQ_INSERT_KEY:                          "insert_key",
+	CHAFA_TERM_SEQ_INSERT_CTRL_KEY:                     "insert_ctrl_key",
+	CHAFA_TERM_SEQ_INSERT_SHIFT_KEY:                    "insert_shift_key",
+	CHAFA_TERM_SEQ_DELETE_KEY:                          "delete_key",
+	CHAFA_TERM_SEQ_DELETE_CTRL_KEY:                     "delete_ctrl_key",
+	CHAFA_TERM_SEQ_DELETE_SHIFT_KEY:                    "delete_shift_key",
+	CHAFA_TERM_SEQ_F1_KEY:                              "f1_key",
+	CHAFA_TERM_SEQ_F1_CTRL_KEY:                         "f1_ctrl_key",
+	CHAFA_TERM_SEQ_F1_SHIFT_KEY:                        "f1_shift_key",
+	CHAFA_TERM_SEQ_F2_KEY:                              "f2_key",
+	CHAFA_TERM_SEQ_F2_CTRL_KEY:                         "f2_ctrl_key",
+	CHAFA_TERM_SEQ_F2_SHIFT_KEY:                        "f2_shift_key",
+	CHAFA_TERM_SEQ_F3_KEY:                              "f3_key",
+	CHAFA_TERM_SEQ_F3_CTRL_KEY:                         "f3_ctrl_key",
+	CHAFA_TERM_SEQ_F3_SHIFT_KEY:                        "f3_shift_key",
+	CHAFA_TERM_SEQ_F4_KEY:                              "f4_key",
+	CHAFA_TERM_SEQ_F4_CTRL_KEY:                         "f4_ctrl_key",
+	CHAFA_TERM_SEQ_F4_SHIFT_KEY:                        "f4_shift_key",
+	CHAFA_TERM_SEQ_F5_KEY:                              "f5_key",
+	CHAFA_TERM_SEQ_F5_CTRL_KEY:                         "f5_ctrl_key",
+	CHAFA_TERM_SEQ_F5_SHIFT_KEY:                        "f5_shift_key",
+	CHAFA_TERM_SEQ_F6_KEY:                              "f6_key",
+	CHAFA_TERM_SEQ_F6_CTRL_KEY:                         "f6_ctrl_key",
+	CHAFA_TERM_SEQ_F6_SHIFT_KEY:                        "f6_shift_key",
+	CHAFA_TERM_SEQ_F7_KEY:                              "f7_key",
+	CHAFA_TERM_SEQ_F7_CTRL_KEY:                         "f7_ctrl_key",
+	CHAFA_TERM_SEQ_F7_SHIFT_KEY:                        "f7_shift_key",
+	CHAFA_TERM_SEQ_F8_KEY:                              "f8_key",
+	CHAFA_TERM_SEQ_F8_CTRL_KEY:                         "f8_ctrl_key",
+	CHAFA_TERM_SEQ_F8_SHIFT_KEY:                        "f8_shift_key",
+	CHAFA_TERM_SEQ_F9_KEY:                              "f9_key",
+	CHAFA_TERM_SEQ_F9_CTRL_KEY:                         "f9_ctrl_key",
+	CHAFA_TERM_SEQ_F9_SHIFT_KEY:                        "f9_shift_key",
+	CHAFA_TERM_SEQ_F10_KEY:                             "f10_key",
+	CHAFA_TERM_SEQ_F10_CTRL_KEY:                        "f10_ctrl_key",
+	CHAFA_TERM_SEQ_F10_SHIFT_KEY:                       "f10_shift_key",
+	CHAFA_TERM_SEQ_F11_KEY:                             "f11_key",
+	CHAFA_TERM_SEQ_F11_CTRL_KEY:                        "f11_ctrl_key",
+	CHAFA_TERM_SEQ_F11_SHIFT_KEY:                       "f11_shift_key",
+	CHAFA_TERM_SEQ_F12_KEY:                             "f12_key",
+	CHAFA_TERM_SEQ_F12_CTRL_KEY:                        "f12_ctrl_key",
+	CHAFA_TERM_SEQ_F12_SHIFT_KEY:                       "f12_shift_key",
+	CHAFA_TERM_SEQ_RESET_COLOR_FG:                      "reset_color_fg",
+	CHAFA_TERM_SEQ_RESET_COLOR_BG:                      "reset_color_bg",
+	CHAFA_TERM_SEQ_RESET_COLOR_FGBG:                    "reset_color_fgbg",
+	CHAFA_TERM_SEQ_RESET_SCROLLING_ROWS:                "reset_scrolling_rows",
+	CHAFA_TERM_SEQ_SAVE_CURSOR_POS:                     "save_cursor_pos",
+	CHAFA_TERM_SEQ_RESTORE_CURSOR_POS:                  "restore_cursor_pos",
+	CHAFA_TERM_SEQ_SET_SIXEL_ADVANCE_DOWN:              "set_sixel_advance_down",
+	CHAFA_TERM_SEQ_SET_SIXEL_ADVANCE_RIGHT:             "set_sixel_advance_right",
+	CHAFA_TERM_SEQ_ENABLE_ALT_SCREEN:                   "enable_alt_screen",
+	CHAFA_TERM_SEQ_DISABLE_ALT_SCREEN:                  "disable_alt_screen",
+	CHAFA_TERM_SEQ_BEGIN_SCREEN_PASSTHROUGH:            "begin_screen_passthrough",
+	CHAFA_TERM_SEQ_END_SCREEN_PASSTHROUGH:              "end_screen_passthrough",
+	CHAFA_TERM_SEQ_BEGIN_TMUX_PASSTHROUGH:              "begin_tmux_passthrough",
+	CHAFA_TERM_SEQ_END_TMUX_PASSTHROUGH:                "end_tmux_passthrough",
+	CHAFA_TERM_SEQ_BEGIN_KITTY_IMMEDIATE_VIRT_IMAGE_V1: "begin_kitty_immediate_virt_image_v1",
+	CHAFA_TERM_SEQ_QUERY_PRIMARY_DEVICE_ATTRIBUTES:     "query_primary_device_attributes",
+	CHAFA_TERM_SEQ_PRIMARY_DEVICE_ATTRIBUTES:           "primary_device_attributes",
+	CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_CELLS:          "query_text_area_size_cells",
+	CHAFA_TERM_SEQ_TEXT_AREA_SIZE_CELLS:                "text_area_size_cells",
+	CHAFA_TERM_SEQ_QUERY_TEXT_AREA_SIZE_PX:             "query_text_area_size_px",
+	CHAFA_TERM_SEQ_TEXT_AREA_SIZE_PX:                   "text_area_size_px",
+	CHAFA_TERM_SEQ_QUERY_CELL_SIZE_PX:                  "query_cell_size_px",
+	CHAFA_TERM_SEQ_CELL_SIZE_PX:                        "cell_size_px",
+}
@@ -0,0 +1,110 @@
+package chafa
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestTermProfileRoundTrip(t *testing.T) {
+	requireLibrary(t)
+
+	tests := []struct {
+		name string
+		env  []string
+	}{
+		{"kitty", []string{"TERM=xterm-kitty", "KITTY_WINDOW_ID=1"}},
+		{"tmux", []string{"TERM=xterm-256color", "TMUX=/tmp/tmux-1000/default,1,0"}},
+		{"mlterm", []string{"TERM=xterm", "MLTERM=3.9.3"}},
+		{"vt100", []string{"TERM=vt100"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected := TermDbDetect(TermDbGetDefault(), tt.env)
+			defer TermInfoUnref(detected)
+
+			want := NewTermProfile(detected)
+			if len(want.Seqs) == 0 {
+				t.Fatal("profile has no sequences")
+			}
+
+			data, err := json.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded TermProfile
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			termInfo, err := decoded.TermInfo()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer TermInfoUnref(termInfo)
+
+			if got := NewTermProfile(termInfo); !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the profile\ngot:  %+v\nwant: %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTermProfileKeepsPassthroughAndInherit(t *testing.T) {
+	requireLibrary(t)
+
+	detected := TermDbDetect(TermDbGetDefault(), []string{"TERM=xterm-kitty", "KITTY_WINDOW_ID=1"})
+	defer TermInfoUnref(detected)
+
+	TermInfoSetIsPixelPassthroughNeeded(detected, CHAFA_PIXEL_MODE_KITTY, true)
+	TermInfoSetInheritSeq(detected, CHAFA_TERM_SEQ_CURSOR_TO_POS, true)
+
+	want := NewTermProfile(detected)
+	if !reflect.DeepEqual(want.PixelPassthroughNeeded, []string{"kitty"}) {
+		t.Fatalf("PixelPassthroughNeeded = %q, want [kitty]", want.PixelPassthroughNeeded)
+	}
+	if len(want.Inherit) == 0 {
+		t.Fatal("profile has no inherited sequences")
+	}
+
+	termInfo, err := want.TermInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer TermInfoUnref(termInfo)
+
+	if got := NewTermProfile(termInfo); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the profile\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestParseTermSeq(t *testing.T) {
+	for _, seq := range []TermSeq{CHAFA_TERM_SEQ_RESET_TERMINAL_SOFT, CHAFA_TERM_SEQ_CURSOR_TO_POS, CHAFA_TERM_SEQ_MAX - 1} {
+		got, err := ParseTermSeq(seq.String())
+		if err != nil || got != seq {
+			t.Errorf("ParseTermSeq(%q) = %v, %v, want %v", seq.String(), got, err, int32(seq))
+		}
+	}
+
+	if _, err := ParseTermSeq("no_such_seq"); err == nil {
+		t.Error("ParseTermSeq accepted an unknown name")
+	}
+}
+
+func TestTermSeqString(t *testing.T) {
+	tests := []struct {
+		seq  TermSeq
+		want string
+	}{
+		{CHAFA_TERM_SEQ_CURSOR_TO_POS, "cursor_to_pos"},
+		{CHAFA_TERM_SEQ_CELL_SIZE_PX, "cell_size_px"},
+		{CHAFA_TERM_SEQ_MAX, "TermSeq(" + strconv.Itoa(int(CHAFA_TERM_SEQ_MAX)) + ")"},
+		{-1, "TermSeq(-1)"},
+	}
+	for _, tt := range tests {
+		if got := tt.seq.String(); got != tt.want {
+			t.Errorf("TermSeq(%d).String() = %q, want %q", int32(tt.seq), got, tt.want)
+		}
+	}
+}
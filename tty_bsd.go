@@ -0,0 +1,10 @@
+//go:build darwin || freebsd
+
+package chafa
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
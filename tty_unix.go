@@ -0,0 +1,61 @@
+//go:build darwin || freebsd || linux
+
+package chafa
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// rawTTY is a terminal put in raw mode by [openRaw], for querying it.
+type rawTTY struct {
+	f       *os.File
+	termios unix.Termios
+}
+
+// openRaw puts the terminal f in raw mode. Reads return after at most 100ms,
+// even without input. [rawTTY.Close] restores the previous mode.
+func openRaw(f *os.File) (*rawTTY, error) {
+	fd := int(f.Fd())
+
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	t := &rawTTY{f: f, termios: *termios}
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP |
+		unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 0
+	termios.Cc[unix.VTIME] = 1
+
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, termios); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Write writes p to the terminal.
+func (t *rawTTY) Write(p []byte) (int, error) {
+	return t.f.Write(p)
+}
+
+// read reads from the terminal. It returns 0 bytes if there was no input for
+// a while.
+func (t *rawTTY) read(buf []byte) (int, error) {
+	n, err := unix.Read(int(t.f.Fd()), buf)
+	if err == unix.EINTR || err == unix.EAGAIN {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Close puts the terminal back in the mode it was in.
+func (t *rawTTY) Close() error {
+	return unix.IoctlSetTermios(int(t.f.Fd()), ioctlSetTermios, &t.termios)
+}
@@ -0,0 +1,93 @@
+package chafa
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// rawTTY is the console put in raw mode by [openRaw], for querying it.
+type rawTTY struct {
+	in, out         *os.File
+	inMode, outMode uint32
+}
+
+// openRaw opens the console of the process for querying it. Queries are
+// written to CONOUT$ with VT processing enabled, and responses read from
+// CONIN$ in raw mode with VT input; f only selects the console, since an
+// output handle such as os.Stdout cannot be read from, and its mode must not
+// lose its output processing. [rawTTY.Close] restores both modes.
+func openRaw(f *os.File) (*rawTTY, error) {
+	var mode uint32
+	if err := windows.GetConsoleMode(windows.Handle(f.Fd()), &mode); err != nil {
+		return nil, err
+	}
+
+	in, err := os.OpenFile("CONIN$", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	out, err := os.OpenFile("CONOUT$", os.O_RDWR, 0)
+	if err != nil {
+		in.Close()
+		return nil, err
+	}
+	t := &rawTTY{in: in, out: out}
+
+	err = windows.GetConsoleMode(windows.Handle(in.Fd()), &t.inMode)
+	if err == nil {
+		err = windows.GetConsoleMode(windows.Handle(out.Fd()), &t.outMode)
+	}
+	if err == nil {
+		raw := t.inMode&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_LINE_INPUT|windows.ENABLE_PROCESSED_INPUT) |
+			windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+		err = windows.SetConsoleMode(windows.Handle(in.Fd()), raw)
+	}
+	if err == nil {
+		vt := t.outMode | windows.ENABLE_PROCESSED_OUTPUT | windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING
+		if err = windows.SetConsoleMode(windows.Handle(out.Fd()), vt); err != nil {
+			windows.SetConsoleMode(windows.Handle(in.Fd()), t.inMode)
+		}
+	}
+	if err != nil {
+		in.Close()
+		out.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Write writes p to the console.
+func (t *rawTTY) Write(p []byte) (int, error) {
+	return t.out.Write(p)
+}
+
+// read reads from the console. It returns 0 bytes if there was no input for
+// a while.
+func (t *rawTTY) read(buf []byte) (int, error) {
+	handle := windows.Handle(t.in.Fd())
+
+	event, err := windows.WaitForSingleObject(handle, 100)
+	if err != nil {
+		return 0, err
+	}
+	if event != windows.WAIT_OBJECT_0 {
+		return 0, nil
+	}
+
+	var n uint32
+	err = windows.ReadFile(handle, buf, &n, nil)
+	return int(n), err
+}
+
+// Close restores the console's modes.
+func (t *rawTTY) Close() error {
+	return errors.Join(
+		windows.SetConsoleMode(windows.Handle(t.in.Fd()), t.inMode),
+		windows.SetConsoleMode(windows.Handle(t.out.Fd()), t.outMode),
+		t.in.Close(),
+		t.out.Close(),
+	)
+}
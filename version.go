@@ -0,0 +1,86 @@
+package chafa
+
+// libraryVersions maps Chafa releases to functions they introduced, oldest
+// first. libchafa does not export its version, so for a library other than
+// the embedded one it is inferred from which of these are present.
+var libraryVersions = []struct {
+	version string
+	markers []string
+}{
+	{"1.6", []string{"chafa_canvas_print", "chafa_term_db_new", "chafa_term_info_new"}},
+	{"1.8", []string{"chafa_canvas_get_char_at", "chafa_canvas_get_raw_colors_at", "chafa_get_n_actual_threads"}},
+	{"1.14", []string{"chafa_canvas_print_rows", "chafa_canvas_set_placement", "chafa_frame_new", "chafa_image_new", "chafa_placement_new"}},
+	{"1.16", []string{"chafa_term_info_emit_query_primary_device_attributes", "chafa_term_info_emit_query_text_area_size_cells", "chafa_term_info_emit_query_cell_size_px"}},
+}
+
+// oldestVersion is reported when none of the markers in libraryVersions are found.
+const oldestVersion = "1.0"
+
+// Version returns the version of the loaded libchafa, e.g. "1.16". It loads
+// the library if needed, and returns an empty string if that fails.
+//
+// The version of the embedded library is known exactly. For any other
+// library it is the newest release whose functions are all present, so it
+// is a lower bound: a system libchafa 1.12 is reported as "1.8". Use
+// [HasFunction] to check for specific functions.
+func Version() string {
+	if err := Init(); err != nil {
+		return ""
+	}
+
+	return libVersion
+}
+
+// HasFunction reports whether the loaded libchafa exports the C function
+// name, e.g. "chafa_canvas_print_rows". It loads the library if needed, and
+// returns false if that fails.
+func HasFunction(name string) bool {
+	if err := Init(); err != nil {
+		return false
+	}
+
+	sym, err := findFunction(libHandle, name)
+	return err == nil && sym != 0
+}
+
+// findFunction looks up the C functions HasFunction reports on. Tests replace
+// it to simulate an older libchafa.
+var findFunction = lookupSymbol
+
+// checkFunction returns the error [Init] reported, or an [*UnsupportedError]
+// if the loaded libchafa does not export the C function name.
+func checkFunction(name string) error {
+	if err := Init(); err != nil {
+		return err
+	}
+	if !HasFunction(name) {
+		return &UnsupportedError{Func: name}
+	}
+
+	return nil
+}
+
+// detectVersion works out the version of the library lib.
+func detectVersion(lib uintptr) string {
+	if GetLibraryReport().Source == LibrarySourceEmbedded {
+		return embeddedChafaVersion
+	}
+
+	return inferVersion(lib)
+}
+
+// inferVersion returns the newest release in libraryVersions whose marker
+// functions are all exported by lib.
+func inferVersion(lib uintptr) string {
+	version := oldestVersion
+	for _, v := range libraryVersions {
+		for _, name := range v.markers {
+			if sym, err := lookupSymbol(lib, name); err != nil || sym == 0 {
+				return version
+			}
+		}
+		version = v.version
+	}
+
+	return version
+}
@@ -0,0 +1,47 @@
+package chafa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInferVersion(t *testing.T) {
+	requireLibrary(t)
+
+	if GetLibraryReport().Source != LibrarySourceEmbedded {
+		t.Skip("the version of a system libchafa is not known")
+	}
+	if got := inferVersion(libHandle); got != embeddedChafaVersion {
+		t.Errorf("inferred version %q for the embedded libchafa %s", got, embeddedChafaVersion)
+	}
+}
+
+func TestUnsupportedFunction(t *testing.T) {
+	requireLibrary(t)
+
+	var missing func(canvas *Canvas, x, y int32) rune
+	bindUnsupported(&missing, "chafa_missing")
+	if got := missing(nil, 1, 2); got != 0 {
+		t.Errorf("unsupported function returned %q", got)
+	}
+
+	err := checkFunction("chafa_missing")
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Func != "chafa_missing" || !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("checkFunction = %v, want an *UnsupportedError for chafa_missing", err)
+	}
+	if err := checkFunction("chafa_canvas_new"); err != nil {
+		t.Errorf("checkFunction(chafa_canvas_new) = %v", err)
+	}
+}
+
+func TestCheckLayout(t *testing.T) {
+	requireLibrary(t)
+
+	if Version() != embeddedChafaVersion {
+		t.Skipf("structs mirror libchafa %s, loaded %s", embeddedChafaVersion, Version())
+	}
+	if err := CheckLayout(); err != nil {
+		t.Error(err)
+	}
+}
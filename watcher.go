@@ -0,0 +1,239 @@
+package chafa
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// ErrWatcherClosed is returned by [Watcher.Run] after [Watcher.Close].
+var ErrWatcherClosed = errors.New("chafa: watcher is closed")
+
+// RedrawFunc draws an image on canvas and prints it. It is called by a
+// [Watcher] whenever the terminal's geometry changes. The canvas belongs to
+// the watcher and must not be used after the function returns.
+type RedrawFunc func(canvas *Canvas, geometry TermGeometry) error
+
+// Watcher keeps an image fitted to the terminal while it is resized. It
+// listens for SIGWINCH, and after a resize works out the new geometry,
+// creates a new canvas of the right size and calls a [RedrawFunc] with it.
+//
+// Resizes are debounced, so that dragging a window edge results in a single
+// redraw once it settles. Windows consoles do not signal resizes; there, and
+// in tests, resizes can be reported with [Watcher.Resize].
+type Watcher struct {
+	tty      *os.File
+	redraw   RedrawFunc
+	debounce time.Duration
+
+	srcWidth, srcHeight int32
+
+	mu       sync.Mutex
+	config   *CanvasConfig
+	canvas   *Canvas
+	geometry TermGeometry
+
+	resized chan *TermGeometry
+}
+
+// WatcherOption configures a [Watcher] created with [NewWatcher].
+type WatcherOption func(*Watcher)
+
+// WithDebounce sets how long a [Watcher] waits for resizes to stop before
+// redrawing. The default is 100ms.
+func WithDebounce(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// NewWatcher creates a [Watcher] for the terminal tty, usually os.Stdout,
+// that fits an image of srcWidth x srcHeight pixels in it and draws it with
+// redraw. Canvases are created with a copy of config, with their geometry
+// set by [FitToTerminal]. The caller should call [Watcher.Close] when done.
+func NewWatcher(
+	tty *os.File,
+	config *CanvasConfig,
+	srcWidth, srcHeight int32,
+	redraw RedrawFunc,
+	opts ...WatcherOption,
+) (*Watcher, error) {
+	if srcWidth <= 0 || srcHeight <= 0 {
+		return nil, errors.New("chafa: invalid image size")
+	}
+	if err := Init(); err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		tty:       tty,
+		redraw:    redraw,
+		debounce:  100 * time.Millisecond,
+		srcWidth:  srcWidth,
+		srcHeight: srcHeight,
+		config:    CanvasConfigCopy(config),
+		resized:   make(chan *TermGeometry, 1),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w, nil
+}
+
+// Run draws the image for the current geometry of the terminal, then redraws
+// it after every resize until ctx is done or redraw returns an error.
+func (w *Watcher) Run(ctx context.Context) error {
+	geometry, err := TerminalGeometry(w.tty)
+	if err != nil {
+		return err
+	}
+	if err := w.update(geometry); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	notifyResize(signals)
+	defer signal.Stop(signals)
+
+	var (
+		timer   *time.Timer
+		expired <-chan time.Time
+		pending *TermGeometry
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-signals:
+
+		case pending = <-w.resized:
+
+		case <-expired:
+			expired = nil
+
+			// Only ask for the size if none was reported with Resize
+			var geometry TermGeometry
+			if pending != nil {
+				geometry, pending = *pending, nil
+			} else {
+				geometry = w.resizedGeometry()
+			}
+			if err := w.update(geometry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Wait for the resizing to settle, keeping the latest injected size
+		if timer == nil {
+			timer = time.NewTimer(w.debounce)
+		} else {
+			timer.Reset(w.debounce)
+		}
+		expired = timer.C
+	}
+}
+
+// Resize reports that the terminal was resized to geometry, as if SIGWINCH
+// had been received but without asking the terminal for its size.
+func (w *Watcher) Resize(geometry TermGeometry) {
+	// Replace any event the watcher hasn't seen yet
+	select {
+	case <-w.resized:
+	default:
+	}
+
+	select {
+	case w.resized <- &geometry:
+	default:
+	}
+}
+
+// Geometry returns the terminal geometry the image was last drawn for.
+func (w *Watcher) Geometry() TermGeometry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.geometry
+}
+
+// Close releases the watcher's canvas and config. It must not be called
+// while [Watcher.Run] is running.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.canvas != nil {
+		CanvasUnRef(w.canvas)
+		w.canvas = nil
+	}
+	if w.config != nil {
+		CanvasConfigUnref(w.config)
+		w.config = nil
+	}
+
+	return nil
+}
+
+// resizedGeometry returns the geometry of the terminal after a resize. The
+// terminal is not queried, since that could swallow the user's input, so if
+// the driver does not report the pixel size the old cell size is kept.
+func (w *Watcher) resizedGeometry() TermGeometry {
+	w.mu.Lock()
+	geometry := w.geometry
+	w.mu.Unlock()
+
+	width, height, widthPx, heightPx, err := windowSize(w.tty)
+	if err != nil || width <= 0 || height <= 0 {
+		return geometry
+	}
+
+	geometry.Width, geometry.Height = width, height
+	if widthPx > 0 && heightPx > 0 {
+		geometry.WidthPx, geometry.HeightPx = widthPx, heightPx
+		geometry.CellWidth, geometry.CellHeight = widthPx/width, heightPx/height
+		geometry.FontRatio = float32(geometry.CellWidth) / float32(geometry.CellHeight)
+	} else if geometry.CellWidth > 0 && geometry.CellHeight > 0 {
+		geometry.WidthPx, geometry.HeightPx = geometry.CellWidth*width, geometry.CellHeight*height
+	}
+
+	return geometry
+}
+
+// update fits a canvas to geometry, replacing the current one if its size
+// changed, and redraws.
+func (w *Watcher) update(geometry TermGeometry) error {
+	w.mu.Lock()
+	if w.config == nil {
+		w.mu.Unlock()
+		return ErrWatcherClosed
+	}
+
+	var oldWidth, oldHeight int32
+	CanvasConfigGetGeometry(w.config, &oldWidth, &oldHeight)
+	width, height := FitToTerminal(w.config, geometry, w.srcWidth, w.srcHeight)
+
+	if w.canvas == nil || width != oldWidth || height != oldHeight ||
+		geometry.CellWidth != w.geometry.CellWidth || geometry.CellHeight != w.geometry.CellHeight {
+		if w.canvas != nil {
+			CanvasUnRef(w.canvas)
+		}
+		w.canvas = CanvasNew(w.config)
+	}
+	w.geometry = geometry
+	canvas := w.canvas
+	w.mu.Unlock()
+
+	return w.redraw(canvas, geometry)
+}
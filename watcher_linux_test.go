@@ -0,0 +1,133 @@
+package chafa
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// setWindowSize sets the size of the pseudo-terminal whose master side is
+// master, as a terminal emulator does when its window is resized.
+func setWindowSize(t *testing.T, master *os.File, cols, rows, widthPx, heightPx uint16) {
+	t.Helper()
+
+	ws := &unix.Winsize{Col: cols, Row: rows, Xpixel: widthPx, Ypixel: heightPx}
+	if err := unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ, ws); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startWatcher runs a [Watcher] on slave, returning a channel that receives
+// the geometry of every redraw.
+func startWatcher(t *testing.T, slave *os.File) (*Watcher, <-chan TermGeometry) {
+	t.Helper()
+
+	config := CanvasConfigNew()
+	defer CanvasConfigUnref(config)
+
+	redraws := make(chan TermGeometry, 16)
+	w, err := NewWatcher(slave, config, 320, 240, func(canvas *Canvas, g TermGeometry) error {
+		CanvasDrawImage(canvas, testImage(32, 24, 0))
+		redraws <- g
+		return nil
+	}, WithDebounce(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		w.Close()
+	})
+
+	return w, redraws
+}
+
+// nextRedraw waits for a redraw, failing the test if there is none.
+func nextRedraw(t *testing.T, redraws <-chan TermGeometry) TermGeometry {
+	t.Helper()
+
+	select {
+	case g := <-redraws:
+		return g
+	case <-time.After(2 * time.Second):
+		t.Fatal("no redraw")
+		return TermGeometry{}
+	}
+}
+
+// expectNoRedraw fails the test if a redraw happens within a while.
+func expectNoRedraw(t *testing.T, redraws <-chan TermGeometry) {
+	t.Helper()
+
+	select {
+	case g := <-redraws:
+		t.Fatalf("unexpected redraw for %dx%d", g.Width, g.Height)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatcherDebouncesResizes(t *testing.T) {
+	requireLibrary(t)
+
+	master, slave := openPTY(t)
+	setWindowSize(t, master, 80, 24, 800, 480)
+	w, redraws := startWatcher(t, slave)
+
+	if g := nextRedraw(t, redraws); g.Width != 80 || g.Height != 24 || g.CellWidth != 10 {
+		t.Errorf("first redraw for %+v, want 80x24 with 10px wide cells", g)
+	}
+
+	// A burst of resizes, as when dragging a window edge, redraws once
+	for i := int32(1); i <= 5; i++ {
+		w.Resize(TermGeometry{Width: 80 + i*10, Height: 24 + i, CellWidth: 10, CellHeight: 20})
+		time.Sleep(5 * time.Millisecond)
+	}
+	if g := nextRedraw(t, redraws); g.Width != 130 || g.Height != 29 {
+		t.Errorf("redraw for %dx%d, want the last size 130x29", g.Width, g.Height)
+	}
+	expectNoRedraw(t, redraws)
+
+	if g := w.Geometry(); g.Width != 130 || g.Height != 29 {
+		t.Errorf("Geometry returned %dx%d, want 130x29", g.Width, g.Height)
+	}
+}
+
+func TestWatcherKeepsInjectedGeometry(t *testing.T) {
+	requireLibrary(t)
+
+	master, slave := openPTY(t)
+	setWindowSize(t, master, 80, 24, 800, 480)
+	w, redraws := startWatcher(t, slave)
+	nextRedraw(t, redraws)
+
+	// A signal during the debounce must not replace the injected size with
+	// the terminal's
+	w.Resize(TermGeometry{Width: 100, Height: 40, CellWidth: 10, CellHeight: 20})
+	time.Sleep(10 * time.Millisecond)
+	if err := unix.Kill(os.Getpid(), unix.SIGWINCH); err != nil {
+		t.Fatal(err)
+	}
+	if g := nextRedraw(t, redraws); g.Width != 100 || g.Height != 40 {
+		t.Errorf("redraw for %dx%d, want the injected 100x40", g.Width, g.Height)
+	}
+
+	// Without an injected size, the terminal is asked again
+	setWindowSize(t, master, 120, 30, 1200, 600)
+	if err := unix.Kill(os.Getpid(), unix.SIGWINCH); err != nil {
+		t.Fatal(err)
+	}
+	if g := nextRedraw(t, redraws); g.Width != 120 || g.Height != 30 || g.WidthPx != 1200 {
+		t.Errorf("redraw for %+v, want 120x30 and 1200px wide", g)
+	}
+}
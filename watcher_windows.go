@@ -0,0 +1,7 @@
+package chafa
+
+import "os"
+
+// notifyResize relays terminal resize signals to c. Windows consoles have
+// none, so this does nothing.
+func notifyResize(c chan<- os.Signal) {}
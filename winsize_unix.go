@@ -0,0 +1,20 @@
+//go:build darwin || freebsd || linux
+
+package chafa
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// windowSize returns the size of the terminal f in cells and, if the
+// terminal reports it, in pixels.
+func windowSize(f *os.File) (width, height, widthPx, heightPx int32, err error) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	return int32(ws.Col), int32(ws.Row), int32(ws.Xpixel), int32(ws.Ypixel), nil
+}
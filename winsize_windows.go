@@ -0,0 +1,21 @@
+package chafa
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowSize returns the size of the console window f in cells. Consoles do
+// not report pixel sizes.
+func windowSize(f *os.File) (width, height, widthPx, heightPx int32, err error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	width = int32(info.Window.Right-info.Window.Left) + 1
+	height = int32(info.Window.Bottom-info.Window.Top) + 1
+
+	return width, height, 0, 0, nil
+}